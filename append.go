@@ -0,0 +1,122 @@
+package easy_csv
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+)
+
+// ErrHeaderMismatch is returned when the header of an existing file differs from the columns of structure
+var ErrHeaderMismatch = errors.New("easy_csv: header of file does not match the structure")
+
+// AppendWriter appends rows of T to a csv file,the header is written only when the file is empty
+type AppendWriter[T any] struct {
+	file   *os.File
+	writer *ClientWriter
+}
+
+// OpenAppend open or create a csv file to append rows of T.
+// The header is written if the file is empty,otherwise the first line of the file must be the header of T,
+// or an error wrapping ErrHeaderMismatch is returned.
+//
+// path: path of the csv file
+//
+// opts: options of the ClientWriter,the comma and Excel options are also used to read the existing header
+func OpenAppend[T any](path string, opts ...ClientWriterOptionFunc) (*AppendWriter[T], error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0666)
+	if err != nil {
+		return nil, err
+	}
+
+	writer := NewClientWriter(file, opts...)
+	title := structureTitle(reflect.TypeOf((*T)(nil)), writer.option)
+	if title == nil {
+		file.Close()
+		return nil, errors.New("type of OpenAppend must be a structure")
+	}
+
+	err = prepareAppend(file, writer, title)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	return &AppendWriter[T]{file: file, writer: writer}, nil
+}
+
+// prepareAppend write the header to an empty file,or check the header of an existing file
+func prepareAppend(file *os.File, writer *ClientWriter, title []string) error {
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+	if info.Size() == 0 {
+		return writer.WriteString2File([][]string{title})
+	}
+
+	readerOpts := []ClientReaderOptionFunc{WithReaderComma(writer.w.Comma), WithReaderFieldsPerRecord(-1)}
+	if writer.option.Excel {
+		readerOpts = append(readerOpts, WithReaderExcel())
+	}
+	header, err := NewClientReader(io.NewSectionReader(file, 0, info.Size()), readerOpts...).Read()
+	if err != nil && err != io.EOF {
+		return err
+	}
+	if !equalRecord(header, title) {
+		return fmt.Errorf("%w: file has %q,structure has %q", ErrHeaderMismatch, header, title)
+	}
+
+	//已有文件不再写入BOM
+	writer.started = true
+
+	//文件末尾没有换行时补一个换行，避免新行接在最后一行后面
+	last := make([]byte, 1)
+	_, err = file.ReadAt(last, info.Size()-1)
+	if err != nil {
+		return err
+	}
+	if last[0] != '\n' {
+		newline := "\n"
+		if writer.w.UseCRLF {
+			newline = "\r\n"
+		}
+		_, err = io.WriteString(file, newline)
+	}
+	return err
+}
+
+func equalRecord(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Write append a row
+func (w *AppendWriter[T]) Write(row T) error {
+	return w.writer.WriteRow2File(row)
+}
+
+// WriteAll append rows
+func (w *AppendWriter[T]) WriteAll(rows []T) error {
+	if len(rows) == 0 {
+		return nil
+	}
+	return w.writer.WriteRows2File(rows)
+}
+
+// Close close the file
+func (w *AppendWriter[T]) Close() error {
+	err := w.writer.Flush()
+	if closeErr := w.file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
@@ -0,0 +1,79 @@
+package easy_csv
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestOpenAppend(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "TestOpenAppend.csv")
+
+	row := testStudentInfo{Name: "李四", Age: 10, Grade: "4年级", Score: 99.01, Email: "lisi@qq.com", Phone: "13322226666"}
+
+	//每次运行都追加数据，表头只写一次
+	for i := 0; i < 2; i++ {
+		writer, err := OpenAppend[testStudentInfo](fileName)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		err = writer.WriteAll([]testStudentInfo{row})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		err = writer.Close()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+	}
+
+	data, err := os.ReadFile(fileName)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	expect := "name,Age,Grade,分数,邮箱,手机号\n" +
+		"李四,10,4年级,99.01,li***i@qq.com,133****6666\n" +
+		"李四,10,4年级,99.01,li***i@qq.com,133****6666\n"
+	if string(data) != expect {
+		t.Errorf("unexpected data:\n%s", data)
+	}
+
+	_, err = OpenAppend[testStudentInfo2](fileName)
+	if !errors.Is(err, ErrHeaderMismatch) {
+		t.Errorf("expected header mismatch, got %v", err)
+	}
+	t.Logf("error: %v\n", err)
+}
+
+func TestOpenAppendExcel(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "TestOpenAppendExcel.csv")
+
+	for i := 0; i < 2; i++ {
+		writer, err := OpenAppend[testExcelBean](fileName, WithWriterExcel(false))
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		err = writer.Write(testExcelBean{Name: "王五", Phone: "13322226666", Zip: "010020"})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		writer.Close()
+	}
+
+	data, err := os.ReadFile(fileName)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	row := "王五,\"=\"\"13322226666\"\"\",\"=\"\"010020\"\"\"\r\n"
+	if string(data) != utf8BOM+"姓名,手机号,邮编\r\n"+row+row {
+		t.Errorf("unexpected data:\n%q", data)
+	}
+}
@@ -0,0 +1,107 @@
+package easy_csv
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+)
+
+// ErrWriterClosed is returned when a closed or aborted file writer is closed again
+var ErrWriterClosed = errors.New("easy_csv: writer is closed")
+
+// AtomicFileWriter is a ClientWriter writing to a temp file in the directory of the target path.
+// The temp file is synced and renamed to the target path on Close,
+// so that a reader of the target path never sees a half-written file.
+// The temp file is removed if Close fails or on Abort.
+type AtomicFileWriter struct {
+	*ClientWriter
+	file   *os.File
+	path   string
+	closed bool
+}
+
+// NewAtomicFileWriter create a writer for path,the file is created with mode 0644 unless it exists
+func NewAtomicFileWriter(path string, opts ...ClientWriterOptionFunc) (*AtomicFileWriter, error) {
+	dir, base := filepath.Split(path)
+	if len(dir) == 0 {
+		dir = "."
+	}
+
+	file, err := os.CreateTemp(dir, "."+base+".*.tmp")
+	if err != nil {
+		return nil, err
+	}
+
+	//保持已有文件的权限
+	mode := os.FileMode(0644)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+	err = file.Chmod(mode)
+	if err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return nil, err
+	}
+
+	return &AtomicFileWriter{
+		ClientWriter: NewClientWriter(file, opts...),
+		file:         file,
+		path:         path,
+	}, nil
+}
+
+// Close flush and sync the temp file,and rename it to the target path.
+// The temp file is removed if any step fails.
+func (w *AtomicFileWriter) Close() error {
+	if w.closed {
+		return ErrWriterClosed
+	}
+	w.closed = true
+
+	err := w.commit()
+	if err != nil {
+		os.Remove(w.file.Name())
+	}
+	return err
+}
+
+func (w *AtomicFileWriter) commit() error {
+	err := w.Flush()
+	if err != nil {
+		w.file.Close()
+		return err
+	}
+	err = w.file.Sync()
+	if err != nil {
+		w.file.Close()
+		return err
+	}
+	err = w.file.Close()
+	if err != nil {
+		return err
+	}
+
+	err = os.Rename(w.file.Name(), w.path)
+	if err != nil {
+		return err
+	}
+
+	//同步目录，保证重命名落盘；部分系统不支持目录同步，忽略其错误
+	if dir, err := os.Open(filepath.Dir(w.path)); err == nil {
+		dir.Sync()
+		dir.Close()
+	}
+	return nil
+}
+
+// Abort discard the written data and remove the temp file,the target path is not changed
+func (w *AtomicFileWriter) Abort() error {
+	if w.closed {
+		return ErrWriterClosed
+	}
+	w.closed = true
+
+	w.file.Close()
+	return os.Remove(w.file.Name())
+}
@@ -0,0 +1,76 @@
+package easy_csv
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestAtomicFileWriter(t *testing.T) {
+	dir := t.TempDir()
+	fileName := filepath.Join(dir, "TestAtomicFileWriter.csv")
+
+	writer, err := NewAtomicFileWriter(fileName)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	err = writer.WriteRow2File(testStudentInfo{Name: "李四", Age: 10}, true)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	//关闭前目标文件不存在
+	if _, err = os.Stat(fileName); !os.IsNotExist(err) {
+		t.Errorf("target file should not exist before Close: %v", err)
+	}
+
+	err = writer.Close()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	data, err := os.ReadFile(fileName)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if string(data) != "name,Age,Grade,分数,邮箱,手机号\n李四,10,,0,,\n" {
+		t.Errorf("unexpected data:\n%s", data)
+	}
+
+	//放弃写入后目标文件保持不变，临时文件被删除
+	writer, err = NewAtomicFileWriter(fileName)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	_ = writer.WriteRow2File(testStudentInfo{Name: "王五"})
+	err = writer.Abort()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	after, err := os.ReadFile(fileName)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if string(after) != string(data) {
+		t.Errorf("target file should not change after Abort:\n%s", after)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if len(entries) != 1 {
+		t.Errorf("temp file should be removed: %v", entries)
+	}
+	if writer.Close() != ErrWriterClosed {
+		t.Error("aborted writer should return ErrWriterClosed")
+	}
+}
@@ -0,0 +1,112 @@
+package easy_csv
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// BatchProgress reports how far ReadInBatches got.
+// The rows after Line,starting at byte Offset of the input,have not been accepted by the callback,
+// a failed batch can be retried by reading again from Offset.
+type BatchProgress struct {
+	Batches int   // batches accepted by the callback
+	Rows    int   // rows of the accepted batches
+	Line    int   // line of the last accepted row,0 if no batch was accepted
+	Offset  int64 // byte offset of the first row not accepted
+}
+
+// BatchError is returned by ReadInBatches when a row can not be read or the callback fails
+type BatchError struct {
+	FirstLine int // line of the first row of the failed batch
+	LastLine  int // line of the last row read into the failed batch
+	Err       error
+}
+
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("batch of lines %d-%d: %v", e.FirstLine, e.LastLine, e.Err)
+}
+
+func (e *BatchError) Unwrap() error {
+	return e.Err
+}
+
+// ReadInBatches read the remaining rows into batches of size rows and call fn with every batch,
+// the last batch may be shorter. T must be a structure type.
+//
+// The batch slice is reused,fn must copy the rows it keeps after it returns.
+// Reading stops at the first error of fn or of a row,the error is returned as a *BatchError.
+func ReadInBatches[T any](reader *ClientReader, size int, fn func(batch []T) error) (BatchProgress, error) {
+	progress := BatchProgress{Offset: reader.offsetBase + reader.r.InputOffset()}
+	if size <= 0 {
+		return progress, errors.New("batch size must be positive")
+	}
+
+	batch := make([]T, 0, size)
+	firstLine, lastLine := 0, 0
+	var zero T
+
+	flush := func(nextOffset int64) error {
+		if len(batch) == 0 {
+			return nil
+		}
+		err := fn(batch)
+		if err != nil {
+			return &BatchError{FirstLine: firstLine, LastLine: lastLine, Err: err}
+		}
+		progress.Batches++
+		progress.Rows += len(batch)
+		progress.Line = lastLine
+		progress.Offset = nextOffset
+		batch = batch[:0]
+		return nil
+	}
+
+	for {
+		row, meta, err := reader.readRecord()
+		if err == io.EOF {
+			//flush会修改progress，先调用再返回
+			err = flush(reader.offsetBase + reader.r.InputOffset())
+			return progress, err
+		}
+		if err != nil {
+			line := lastLine
+			var parseErr *csv.ParseError
+			var rowErr *RowError
+			var limitErr *LimitError
+			switch {
+			case errors.As(err, &parseErr):
+				line = reader.lineBase + parseErr.Line
+			case errors.As(err, &rowErr):
+				line = rowErr.Line
+			case errors.As(err, &limitErr):
+				line = limitErr.Line
+			}
+			if len(batch) == 0 {
+				firstLine = line
+			}
+			return progress, &BatchError{FirstLine: firstLine, LastLine: line, Err: err}
+		}
+
+		if len(batch) == 0 {
+			firstLine = meta.line
+		}
+		lastLine = meta.line
+
+		//复用的元素先清零，避免残留上一批的字段
+		batch = append(batch, zero)
+		err = unmarshalOneDSlice(row, &batch[len(batch)-1], reader.option, meta)
+		if err != nil {
+			reader.progress.fail()
+			return progress, &BatchError{FirstLine: firstLine, LastLine: lastLine, Err: err}
+		}
+
+		if len(batch) == size {
+			err = flush(reader.offsetBase + reader.r.InputOffset())
+			if err != nil {
+				return progress, err
+			}
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package easy_csv
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+type testBatchRow struct {
+	ID   int
+	Name string
+}
+
+func TestReadInBatches(t *testing.T) {
+	data := "1,a\n2,b\n3,c\n4,d\n5,e\n"
+	reader := NewClientReader(strings.NewReader(data))
+
+	sizes := make([]int, 0)
+	ids := make([]int, 0)
+	progress, err := ReadInBatches(reader, 2, func(batch []testBatchRow) error {
+		sizes = append(sizes, len(batch))
+		for _, row := range batch {
+			ids = append(ids, row.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if len(sizes) != 3 || sizes[2] != 1 || len(ids) != 5 || ids[4] != 5 {
+		t.Errorf("unexpected batches: %v %v", sizes, ids)
+	}
+	if progress.Batches != 3 || progress.Rows != 5 || progress.Line != 5 || progress.Offset != int64(len(data)) {
+		t.Errorf("unexpected progress: %+v", progress)
+	}
+}
+
+func TestReadInBatchesRetry(t *testing.T) {
+	data := "1,a\n2,b\n3,c\n4,d\n5,e\n"
+	reader := NewClientReader(strings.NewReader(data))
+
+	failed := errors.New("insert failed")
+	progress, err := ReadInBatches(reader, 2, func(batch []testBatchRow) error {
+		if batch[0].ID == 3 {
+			return failed
+		}
+		return nil
+	})
+
+	var batchErr *BatchError
+	if !errors.As(err, &batchErr) || !errors.Is(err, failed) {
+		t.Errorf("unexpected error: %v", err)
+		return
+	}
+	if batchErr.FirstLine != 3 || batchErr.LastLine != 4 {
+		t.Errorf("unexpected lines: %+v", batchErr)
+	}
+	if progress.Batches != 1 || progress.Rows != 2 || progress.Line != 2 || progress.Offset != 8 {
+		t.Errorf("unexpected progress: %+v", progress)
+		return
+	}
+
+	//从Offset重新读取失败的批次
+	reader = NewClientReader(strings.NewReader(data[progress.Offset:]))
+	ids := make([]int, 0)
+	_, err = ReadInBatches(reader, 2, func(batch []testBatchRow) error {
+		for _, row := range batch {
+			ids = append(ids, row.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if len(ids) != 3 || ids[0] != 3 {
+		t.Errorf("unexpected ids: %v", ids)
+	}
+}
+
+func TestReadInBatchesRowError(t *testing.T) {
+	reader := NewClientReader(strings.NewReader("1,a\nx,b\n"))
+
+	progress, err := ReadInBatches(reader, 10, func(batch []testBatchRow) error {
+		return nil
+	})
+	var batchErr *BatchError
+	if !errors.As(err, &batchErr) || batchErr.LastLine != 2 {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if progress.Rows != 0 {
+		t.Errorf("unexpected progress: %+v", progress)
+	}
+}
+
+func TestReadInBatchesErrorLine(t *testing.T) {
+	//RaggedReject没有收集器时，错误行号来自RowError
+	reader := NewClientReader(strings.NewReader("1,a\n2,b\n3\n"), WithReaderRaggedPolicy(RaggedReject))
+	_, err := ReadInBatches(reader, 10, func(batch []testBatchRow) error {
+		return nil
+	})
+	var batchErr *BatchError
+	if !errors.As(err, &batchErr) || batchErr.FirstLine != 1 || batchErr.LastLine != 3 {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	//超过限制时，错误行号来自LimitError
+	reader = NewClientReader(strings.NewReader("1,a\n2,b\n3,c,d\n"), WithReaderMaxFields(2))
+	_, err = ReadInBatches(reader, 10, func(batch []testBatchRow) error {
+		return nil
+	})
+	if !errors.As(err, &batchErr) || batchErr.LastLine != 3 || !errors.Is(err, ErrLimitExceeded) {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
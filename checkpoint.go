@@ -0,0 +1,86 @@
+package easy_csv
+
+import (
+	"errors"
+	"io"
+	"strings"
+)
+
+// Checkpoint is the position of a ClientReader at a record boundary,
+// it can be persisted as JSON and used to resume reading the same input
+type Checkpoint struct {
+	Offset int64    `json:"offset"` // byte offset of the next record
+	Line   int      `json:"line"`   // lines of input before the next record
+	Rows   int      `json:"rows"`   // records read,the header is counted
+	Header []string `json:"header"` // the first record,nil if no record was read
+	Comma  rune     `json:"comma"`  // field delimiter of the input
+}
+
+// Checkpoint return the position after the last record read
+func (reader *ClientReader) Checkpoint() Checkpoint {
+	return Checkpoint{
+		Offset: reader.offsetBase + reader.r.InputOffset(),
+		Line:   reader.lines,
+		Rows:   reader.rows,
+		Header: append([]string(nil), reader.header...),
+		Comma:  reader.r.Comma,
+	}
+}
+
+// Header return the first record read,or the header of the checkpoint a reader is resumed from
+func (reader *ClientReader) Header() []string {
+	return append([]string(nil), reader.header...)
+}
+
+// ResumeClientReader create a reader continuing from checkpoint.
+// input is seeked to the offset of checkpoint,the records before it are not read again.
+// The options should be the same as the reader the checkpoint is taken from.
+func ResumeClientReader(input io.ReadSeeker, checkpoint Checkpoint, opts ...ClientReaderOptionFunc) (*ClientReader, error) {
+	if checkpoint.Offset < 0 || checkpoint.Line < 0 {
+		return nil, errors.New("invalid checkpoint")
+	}
+
+	option := &ClientReaderOption{}
+	for _, o := range opts {
+		o(option)
+	}
+
+	_, err := input.Seek(checkpoint.Offset, io.SeekStart)
+	if err != nil {
+		return nil, err
+	}
+	return newClientReader(input, option, &checkpoint), nil
+}
+
+// resume restore the state of reader from checkpoint
+func (reader *ClientReader) resume(checkpoint *Checkpoint) {
+	reader.rows = checkpoint.Rows
+	reader.scanned = checkpoint.Rows
+	reader.lines = checkpoint.Line
+	reader.header = append([]string(nil), checkpoint.Header...)
+
+	if len(reader.header) == 0 {
+		return
+	}
+	//表头已读取，字段数按表头检查
+	if reader.option.RaggedPolicy != RaggedStrict {
+		if reader.width == 0 {
+			reader.width = len(reader.header)
+		}
+	} else if reader.r.FieldsPerRecord == 0 {
+		reader.r.FieldsPerRecord = len(reader.header)
+	}
+}
+
+// mark remember the header and the end line of a record read
+func (reader *ClientReader) mark(meta *rowMeta) {
+	if reader.header == nil {
+		reader.header = append([]string(nil), meta.raw...)
+	}
+
+	//带引号的字段可以跨行
+	reader.lines = meta.line
+	for _, field := range meta.raw {
+		reader.lines += strings.Count(field, "\n")
+	}
+}
@@ -0,0 +1,64 @@
+package easy_csv
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestCheckpointResume(t *testing.T) {
+	data := "ID,Name\n1,a\n2,\"b\nb\"\n3,c\n4,d,x\n"
+	reader := NewClientReader(strings.NewReader(data))
+
+	for i := 0; i < 3; i++ {
+		if _, err := reader.Read(); err != nil {
+			t.Error(err)
+			return
+		}
+	}
+	checkpoint := reader.Checkpoint()
+	if checkpoint.Offset != int64(strings.Index(data, "3,c")) || checkpoint.Line != 4 || checkpoint.Rows != 3 {
+		t.Errorf("unexpected checkpoint: %+v", checkpoint)
+	}
+
+	//检查点可以持久化
+	saved, err := json.Marshal(checkpoint)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	restored := Checkpoint{}
+	err = json.Unmarshal(saved, &restored)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	resumed, err := ResumeClientReader(strings.NewReader(data), restored)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if header := resumed.Header(); len(header) != 2 || header[1] != "Name" {
+		t.Errorf("unexpected header: %v", header)
+	}
+
+	row := testBatchRow{}
+	err = resumed.ReadRowFromFile(&row)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if row.ID != 3 || row.Name != "c" {
+		t.Errorf("unexpected row: %+v", row)
+	}
+	if next := resumed.Checkpoint(); next.Line != 5 || next.Rows != 4 {
+		t.Errorf("unexpected checkpoint: %+v", next)
+	}
+
+	//字段数仍按表头检查
+	_, err = resumed.Read()
+	if err == nil {
+		t.Error("expected a field count error")
+	}
+}
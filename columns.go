@@ -0,0 +1,53 @@
+package easy_csv
+
+// ColumnSpec selects a field of structure to be written as a column
+type ColumnSpec struct {
+	Field string // field name,or column name of the tag
+	Title string // column name written in header,the column name of the tag is used if it is empty
+}
+
+// WithWriterColumns write only the given columns of structure in the given order,
+// so that a structure can produce several export layouts.
+// The formatting,transforms and masking of the tags are kept.
+func WithWriterColumns(columns ...ColumnSpec) ClientWriterOptionFunc {
+	return func(opt *ClientWriterOption) {
+		opt.Columns = columns
+	}
+}
+
+// WriteRowWithNames Write a line of data with the fields specified by names in order.
+//
+// names: The parameter names is field names of structure in order to specify column of file
+//
+// structure: The parameter structure is a structure or a structure pointer
+func (writer *ClientWriter) WriteRowWithNames(names []string, structure interface{}, setTitle ...bool) error {
+	flag := false
+	if len(setTitle) > 0 {
+		flag = setTitle[0]
+	}
+	return writer.writeStructure(structure, flag, writer.optionWithNames(names))
+}
+
+// WriteRowsWithNames Write multiple lines of data with the fields specified by names in order,
+// it is the counterpart of ClientReader.ReadRowsFromFileWithNames.
+//
+// names: The parameter names is field names of structure in order to specify column of file
+//
+// list: The parameter list is a list pointer,the item of list must be a structure or a structure pointer
+func (writer *ClientWriter) WriteRowsWithNames(names []string, list interface{}, setTitle ...bool) error {
+	flag := false
+	if len(setTitle) > 0 {
+		flag = setTitle[0]
+	}
+	return writer.writeList(list, flag, writer.optionWithNames(names))
+}
+
+// optionWithNames return a copy of the option of writer,which selects the columns of names
+func (writer *ClientWriter) optionWithNames(names []string) *ClientWriterOption {
+	option := *writer.option
+	option.Columns = make([]ColumnSpec, len(names))
+	for i, name := range names {
+		option.Columns[i] = ColumnSpec{Field: name}
+	}
+	return &option
+}
@@ -0,0 +1,66 @@
+package easy_csv
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestClientWriter_WriteRowsWithNames(t *testing.T) {
+	list := []testStudentInfo{
+		{Name: "王五", Age: 12, Grade: "6年级", Score: 123.01, Email: "wangwu@qq.com", Phone: "13311112222"},
+		{Name: "张三", Age: 11, Grade: "5年级", Score: 123.02, Email: "zh@qq.com", Phone: "13322225559"},
+	}
+
+	buf := &strings.Builder{}
+	writer := NewClientWriter(buf)
+	err := writer.WriteRowsWithNames([]string{"Phone", "Name", "分数"}, list, true)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	expect := "手机号,name,分数\n" +
+		"133****2222,王五,123.01\n" +
+		"133****5559,张三,123.02\n"
+	if buf.String() != expect {
+		t.Errorf("unexpected data:\n%s", buf.String())
+	}
+
+	//写入器的其他调用仍输出全部列
+	buf.Reset()
+	err = writer.WriteRow2File(list[0])
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if buf.String() != "王五,12,6年级,123.01,wa***u@qq.com,133****2222\n" {
+		t.Errorf("unexpected data:\n%s", buf.String())
+	}
+
+	err = writer.WriteRowsWithNames([]string{"Unknown"}, list)
+	if err == nil {
+		t.Error("unknown column should be an error")
+	}
+}
+
+func TestClientWriter_WithWriterColumns(t *testing.T) {
+	buf := &strings.Builder{}
+	writer := NewClientWriter(buf, WithWriterColumns(
+		ColumnSpec{Field: "Name", Title: "Student"},
+		ColumnSpec{Field: "Grade"},
+	))
+
+	enc := writer.NewEncoder()
+	err := enc.Encode(testStudentInfo{Name: "王五", Grade: "6年级"})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	err = enc.Close()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if buf.String() != "Student,Grade\n王五,6年级\n" {
+		t.Errorf("unexpected data:\n%s", buf.String())
+	}
+}
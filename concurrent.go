@@ -0,0 +1,198 @@
+package easy_csv
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+)
+
+// ConcurrentWriter writes structures from many goroutines to a ClientWriter.
+// Rows are marshaled by the calling goroutines and written in order of arrival by a single goroutine,
+// the rows of one call are written together,so lines never interleave.
+// A call blocks while the buffer is full.
+type ConcurrentWriter struct {
+	writer *ClientWriter
+	option *ConcurrentOption
+	rows   chan concurrentBatch
+	done   chan struct{}
+
+	mu     sync.RWMutex // guards closed,held for reading while sending to rows
+	closed bool
+
+	errMu     sync.Mutex
+	errs      []error
+	writeErr  error // the first error of writing,the later rows are dropped
+	wroteHead bool  // used only by the writing goroutine
+}
+
+type concurrentBatch struct {
+	v       interface{}
+	records [][]string
+}
+
+type ConcurrentOption struct {
+	// Buffer the number of calls buffered before a call blocks,64 by default
+	Buffer int
+
+	// Header write the header of the structure written first,it is true by default
+	Header bool
+}
+
+type ConcurrentOptionFunc func(opt *ConcurrentOption)
+
+func WithConcurrentBuffer(buffer int) ConcurrentOptionFunc {
+	return func(opt *ConcurrentOption) {
+		opt.Buffer = buffer
+	}
+}
+
+func WithConcurrentHeader(header bool) ConcurrentOptionFunc {
+	return func(opt *ConcurrentOption) {
+		opt.Header = header
+	}
+}
+
+// NewConcurrentWriter create a ConcurrentWriter writing to writer.
+// writer must not be used directly until the ConcurrentWriter is closed.
+func (writer *ClientWriter) NewConcurrentWriter(opts ...ConcurrentOptionFunc) *ConcurrentWriter {
+	option := &ConcurrentOption{
+		Buffer: 64,
+		Header: true,
+	}
+	for _, o := range opts {
+		o(option)
+	}
+	if option.Buffer < 0 {
+		option.Buffer = 0
+	}
+
+	cw := &ConcurrentWriter{
+		writer: writer,
+		option: option,
+		rows:   make(chan concurrentBatch, option.Buffer),
+		done:   make(chan struct{}),
+	}
+	go cw.run()
+	return cw
+}
+
+// WriteRow2File write a row,it is safe for concurrent use
+//
+// structure: a structure or a structure pointer
+func (cw *ConcurrentWriter) WriteRow2File(structure interface{}) error {
+	records, err := marshalStructure(structure, false, cw.writer.option)
+	if err != nil {
+		cw.addError(err)
+		return err
+	}
+	return cw.send(concurrentBatch{v: structure, records: records})
+}
+
+// WriteRows2File write the rows of list together,it is safe for concurrent use
+//
+// list: a list or a list pointer,the item of list must be a structure or a structure pointer
+func (cw *ConcurrentWriter) WriteRows2File(list interface{}) error {
+	records, err := marshalList(list, false, cw.writer.option)
+	if err != nil {
+		cw.addError(err)
+		return err
+	}
+	if len(records) == 0 {
+		return nil
+	}
+	return cw.send(concurrentBatch{v: list, records: records})
+}
+
+// Errors return the errors of all calls and of writing,in the order they occurred
+func (cw *ConcurrentWriter) Errors() []error {
+	cw.errMu.Lock()
+	defer cw.errMu.Unlock()
+
+	errs := make([]error, len(cw.errs))
+	copy(errs, cw.errs)
+	return errs
+}
+
+// Close wait until the buffered rows are written and flush them,
+// and return all errors joined.
+// The underlying io.Writer is not closed.
+func (cw *ConcurrentWriter) Close() error {
+	cw.mu.Lock()
+	if cw.closed {
+		cw.mu.Unlock()
+		return ErrWriterClosed
+	}
+	cw.closed = true
+	close(cw.rows)
+	cw.mu.Unlock()
+
+	<-cw.done
+	return errors.Join(cw.Errors()...)
+}
+
+func (cw *ConcurrentWriter) send(batch concurrentBatch) error {
+	cw.mu.RLock()
+	defer cw.mu.RUnlock()
+
+	if cw.closed {
+		return ErrWriterClosed
+	}
+	if err := cw.failed(); err != nil {
+		return err
+	}
+	cw.rows <- batch
+	return nil
+}
+
+// run write the batches until rows is closed,it is the only goroutine using writer
+func (cw *ConcurrentWriter) run() {
+	defer close(cw.done)
+
+	for batch := range cw.rows {
+		if cw.failed() != nil {
+			continue
+		}
+
+		records := batch.records
+		if cw.option.Header && !cw.wroteHead {
+			title := structureTitle(reflect.TypeOf(batch.v), cw.writer.option)
+			records = append([][]string{title}, records...)
+			cw.wroteHead = true
+		}
+
+		cw.writer.setHeader(batch.v, cw.writer.option)
+		err := cw.writer.writeRecords(records)
+		//缓冲区为空时刷新，繁忙时由csv.Writer的缓冲合并写入
+		if err == nil && len(cw.rows) == 0 {
+			err = cw.writer.Flush()
+		}
+		if err != nil {
+			cw.fail(err)
+		}
+	}
+
+	if cw.failed() == nil {
+		if err := cw.writer.Flush(); err != nil {
+			cw.fail(err)
+		}
+	}
+}
+
+func (cw *ConcurrentWriter) failed() error {
+	cw.errMu.Lock()
+	defer cw.errMu.Unlock()
+	return cw.writeErr
+}
+
+func (cw *ConcurrentWriter) fail(err error) {
+	cw.errMu.Lock()
+	defer cw.errMu.Unlock()
+	cw.writeErr = err
+	cw.errs = append(cw.errs, err)
+}
+
+func (cw *ConcurrentWriter) addError(err error) {
+	cw.errMu.Lock()
+	defer cw.errMu.Unlock()
+	cw.errs = append(cw.errs, err)
+}
@@ -0,0 +1,90 @@
+package easy_csv
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+func TestConcurrentWriter(t *testing.T) {
+	buf := &bytes.Buffer{}
+	cw := NewClientWriter(buf).NewConcurrentWriter(WithConcurrentBuffer(4))
+
+	var wg sync.WaitGroup
+	for g := 0; g < 10; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 50; i++ {
+				//含逗号和换行的字段也不能交错
+				info := testStudentInfo{Name: fmt.Sprintf("学生,%d\n%d", g, i), Age: g*100 + i}
+				if err := cw.WriteRow2File(&info); err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	err := cw.Close()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	records, err := csv.NewReader(buf).ReadAll()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if len(records) != 501 || records[0][0] != "name" {
+		t.Errorf("unexpected rows: %d", len(records))
+		return
+	}
+
+	seen := make(map[int]bool)
+	for _, record := range records[1:] {
+		age, err := strconv.Atoi(record[1])
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if record[0] != fmt.Sprintf("学生,%d\n%d", age/100, age%100) {
+			t.Errorf("interleaved row: %q", record)
+		}
+		seen[age] = true
+	}
+	if len(seen) != 500 {
+		t.Errorf("unexpected distinct rows: %d", len(seen))
+	}
+
+	if err = cw.WriteRow2File(testStudentInfo{}); err != ErrWriterClosed {
+		t.Errorf("expected ErrWriterClosed, got %v", err)
+	}
+}
+
+func TestConcurrentWriterErrors(t *testing.T) {
+	cw := NewClientWriter(testFailWriter{}).NewConcurrentWriter()
+
+	err := cw.WriteRow2File(testStudentInfo{Name: "王五"})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	err = cw.WriteRow2File(1)
+	if err == nil {
+		t.Error("expected an error for a non-structure value")
+	}
+
+	err = cw.Close()
+	if err == nil {
+		t.Error("errors should be returned by Close")
+	}
+	if len(cw.Errors()) != 2 {
+		t.Errorf("unexpected errors: %v", cw.Errors())
+	}
+}
@@ -0,0 +1,98 @@
+package easy_csv
+
+import (
+	"context"
+	"fmt"
+)
+
+// StopError is returned by the context-aware methods when the context is done
+type StopError struct {
+	// Row the 1-based number of the record where processing stopped,
+	// counted from the first record read or written by the client,not by the call,the header included
+	Row int
+	Err error
+}
+
+func (e *StopError) Error() string {
+	return fmt.Sprintf("stopped at row %d: %v", e.Row, e.Err)
+}
+
+func (e *StopError) Unwrap() error {
+	return e.Err
+}
+
+// ReadAllContext is ReadAll checking ctx before every record
+func (reader *ClientReader) ReadAllContext(ctx context.Context) ([][]string, error) {
+	records, _, err := reader.readRecordsContext(ctx)
+	return records, err
+}
+
+// ReadRowsFromFileContext is ReadRowsFromFile checking ctx before every record
+//
+// list: The parameter list is a list pointer,the item of list must be a structure or a structure pointer
+func (reader *ClientReader) ReadRowsFromFileContext(ctx context.Context, list interface{}) error {
+	rows, metas, err := reader.readRecordsContext(ctx)
+	if err != nil {
+		return err
+	}
+	err = unmarshalTwoDSlice(rows, list, reader.option, metas)
+	if err != nil {
+		reader.progress.fail()
+	}
+	return err
+}
+
+// ReadRowsFromFileWithNamesContext is ReadRowsFromFileWithNames checking ctx before every record
+//
+// names: The parameter names is field names of structure in order to specify column of file to structure
+//
+// list: The parameter list is a list pointer,the item of list must be a structure or a structure pointer
+func (reader *ClientReader) ReadRowsFromFileWithNamesContext(ctx context.Context, names []string, list interface{}) error {
+	rows, metas, err := reader.readRecordsContext(ctx)
+	if err != nil {
+		return err
+	}
+	err = unmarshalTwoDSliceWithNames(names, rows, list, reader.option, metas)
+	if err != nil {
+		reader.progress.fail()
+	}
+	return err
+}
+
+// WriteRows2FileContext is WriteRows2File checking ctx before every record.
+// The records written before ctx is done are flushed.
+//
+// list: The parameter list is a list pointer,the item of list must be a structure or a structure pointer
+func (writer *ClientWriter) WriteRows2FileContext(ctx context.Context, list interface{}, setTitle ...bool) error {
+	flag := false
+	if len(setTitle) > 0 {
+		flag = setTitle[0]
+	}
+
+	records, err := marshalList(list, flag, writer.option)
+	if err != nil {
+		return err
+	}
+	writer.setHeader(list, writer.option)
+	return writer.WriteString2FileContext(ctx, records)
+}
+
+// WriteString2FileContext is WriteString2File checking ctx before every record.
+// The records written before ctx is done are flushed.
+func (writer *ClientWriter) WriteString2FileContext(ctx context.Context, data [][]string) error {
+	for i := range data {
+		if err := ctx.Err(); err != nil {
+			if flushErr := writer.Flush(); flushErr != nil {
+				return flushErr
+			}
+			//行号从写入者的第一条记录开始计算
+			return &StopError{Row: writer.progress.stats.Rows + 1, Err: err}
+		}
+
+		err := writer.writeRecords(data[i : i+1])
+		if err != nil {
+			return err
+		}
+	}
+	return writer.Flush()
+}
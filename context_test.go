@@ -0,0 +1,121 @@
+package easy_csv
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// testCountdownContext is canceled after Err is called n times
+type testCountdownContext struct {
+	context.Context
+	n int
+}
+
+func (c *testCountdownContext) Err() error {
+	if c.n <= 0 {
+		return context.Canceled
+	}
+	c.n--
+	return nil
+}
+
+func TestReadRowsFromFileContext(t *testing.T) {
+	reader := NewClientReader(strings.NewReader("1,a\n2,b\n3,c\n"))
+
+	list := make([]testBatchRow, 0)
+	err := reader.ReadRowsFromFileContext(&testCountdownContext{Context: context.Background(), n: 2}, &list)
+
+	var stopErr *StopError
+	if !errors.As(err, &stopErr) || !errors.Is(err, context.Canceled) {
+		t.Errorf("unexpected error: %v", err)
+		return
+	}
+	if stopErr.Row != 3 {
+		t.Errorf("unexpected row: %d", stopErr.Row)
+	}
+
+	//未取消时与ReadRowsFromFile一致
+	reader = NewClientReader(strings.NewReader("1,a\n2,b\n3,c\n"))
+	err = reader.ReadRowsFromFileContext(context.Background(), &list)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if len(list) != 3 || list[2].Name != "c" {
+		t.Errorf("unexpected list: %v", list)
+	}
+}
+
+func TestWriteRows2FileContext(t *testing.T) {
+	buf := &bytes.Buffer{}
+	writer := NewClientWriter(buf)
+
+	list := []testBatchRow{{ID: 1, Name: "a"}, {ID: 2, Name: "b"}, {ID: 3, Name: "c"}}
+	err := writer.WriteRows2FileContext(&testCountdownContext{Context: context.Background(), n: 2}, list, true)
+
+	var stopErr *StopError
+	if !errors.As(err, &stopErr) || stopErr.Row != 3 {
+		t.Errorf("unexpected error: %v", err)
+		return
+	}
+	//取消前写入的记录已刷新
+	if buf.String() != "ID,Name\n1,a\n" {
+		t.Errorf("unexpected data:\n%s", buf.String())
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = writer.WriteString2FileContext(ctx, [][]string{{"4", "d"}})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestReadAllContextAfterHeader(t *testing.T) {
+	reader := NewClientReader(strings.NewReader("ID,Name\n1,a\n"))
+	if _, err := reader.Read(); err != nil {
+		t.Error(err)
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := reader.ReadAllContext(ctx)
+	var stopErr *StopError
+	if !errors.As(err, &stopErr) || stopErr.Row != 2 {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestWriteString2FileContextAbsoluteRow(t *testing.T) {
+	buf := &bytes.Buffer{}
+	writer := NewClientWriter(buf)
+	err := writer.WriteString2File([][]string{{"ID", "Name"}, {"1", "a"}})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	//行号从写入者的第一条记录开始计算，与读取一侧一致
+	err = writer.WriteString2FileContext(&testCountdownContext{Context: context.Background(), n: 1}, [][]string{{"2", "b"}, {"3", "c"}})
+	var stopErr *StopError
+	if !errors.As(err, &stopErr) || stopErr.Row != 4 {
+		t.Errorf("unexpected error: %v", err)
+		return
+	}
+
+	reader := NewClientReader(strings.NewReader(buf.String()))
+	for i := 0; i < 2; i++ {
+		if _, err = reader.Read(); err != nil {
+			t.Error(err)
+			return
+		}
+	}
+	_, err = reader.ReadAllContext(&testCountdownContext{Context: context.Background(), n: 1})
+	if !errors.As(err, &stopErr) || stopErr.Row != 4 {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,105 @@
+package easy_csv
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// KeyRing holds the AES keys used by the `encrypt` tag option.
+// Values are encrypted with the current key,and every key of the ring can decrypt,
+// so that keys can be rotated without re-encrypting old files.
+//
+// An encrypted cell is formatted as '<key id>:<base64 of nonce and ciphertext>'.
+type KeyRing struct {
+	current string
+	keys    map[string]cipher.AEAD
+}
+
+// NewKeyRing create a key ring.
+//
+// currentID string: id of the key used to encrypt,it must be one of keys
+//
+// keys map[string][]byte: AES-128, AES-192 or AES-256 keys by id,an id must not contain ':'
+func NewKeyRing(currentID string, keys map[string][]byte) (*KeyRing, error) {
+	if _, ok := keys[currentID]; !ok {
+		return nil, errors.New(fmt.Sprintf("current key %s is not in keys", currentID))
+	}
+
+	ring := &KeyRing{
+		current: currentID,
+		keys:    make(map[string]cipher.AEAD, len(keys)),
+	}
+	for id, key := range keys {
+		if len(id) == 0 || strings.Contains(id, ":") {
+			return nil, errors.New(fmt.Sprintf("invalid key id %q", id))
+		}
+
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, fmt.Errorf("key %s: %w", id, err)
+		}
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, fmt.Errorf("key %s: %w", id, err)
+		}
+		ring.keys[id] = aead
+	}
+	return ring, nil
+}
+
+// Encrypt encrypt a value with the current key by AES-GCM
+func (ring *KeyRing) Encrypt(plain string) (string, error) {
+	aead := ring.keys[ring.current]
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+
+	//密钥ID作为附加数据，防止密文被换到其他密钥下
+	sealed := aead.Seal(nonce, nonce, []byte(plain), []byte(ring.current))
+	return ring.current + ":" + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt decrypt a value encrypted by Encrypt with any key of the ring
+func (ring *KeyRing) Decrypt(cell string) (string, error) {
+	id, data, ok := strings.Cut(cell, ":")
+	if !ok {
+		return "", errors.New("invalid encrypted value")
+	}
+
+	aead, ok := ring.keys[id]
+	if !ok {
+		return "", errors.New(fmt.Sprintf("unknown key %s", id))
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		return "", fmt.Errorf("invalid encrypted value: %w", err)
+	}
+	if len(sealed) < aead.NonceSize() {
+		return "", errors.New("invalid encrypted value")
+	}
+
+	plain, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], []byte(id))
+	if err != nil {
+		return "", err
+	}
+	return string(plain), nil
+}
+
+// tokenize return the keyed HMAC-SHA256 of value in hex,the same value and key always give the same token
+func tokenize(value string, key []byte) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(value))
+	return hex.EncodeToString(mac.Sum(nil))
+}
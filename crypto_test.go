@@ -0,0 +1,99 @@
+package easy_csv
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+type testSecretBean struct {
+	Name  string `csv:"name"`
+	Phone string `csv:"phone,tokenize"`
+	IDNo  string `csv:"id,trim,encrypt"`
+}
+
+func TestKeyRing(t *testing.T) {
+	oldRing, err := NewKeyRing("k1", map[string][]byte{"k1": bytes.Repeat([]byte{1}, 32)})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	cell, err := oldRing.Encrypt("110101199003071234")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	//轮换密钥后旧密钥加密的数据仍可解密
+	ring, err := NewKeyRing("k2", map[string][]byte{
+		"k1": bytes.Repeat([]byte{1}, 32),
+		"k2": bytes.Repeat([]byte{2}, 16),
+	})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	plain, err := ring.Decrypt(cell)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if plain != "110101199003071234" {
+		t.Errorf("unexpected plain text: %s", plain)
+	}
+
+	_, err = NewKeyRing("k3", map[string][]byte{"k1": bytes.Repeat([]byte{1}, 32)})
+	if err == nil {
+		t.Error("current key must be in keys")
+	}
+}
+
+func TestClientWriter_WriteRows2FileSecret(t *testing.T) {
+	ring, err := NewKeyRing("k1", map[string][]byte{"k1": bytes.Repeat([]byte{1}, 32)})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	list := []testSecretBean{
+		{Name: "王五", Phone: "13322226666", IDNo: " 110101199003071234 "},
+		{Name: "张三", Phone: "13322226666", IDNo: "110101199003075678"},
+	}
+
+	buf := &bytes.Buffer{}
+	writer := NewClientWriter(buf, WithWriterTokenKey([]byte("secret")), WithWriterKeyRing(ring))
+	err = writer.WriteRows2File(list, true)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	t.Logf("data:\n%s", buf.String())
+
+	//相同的值生成相同的token
+	records, err := NewClientReader(strings.NewReader(buf.String())).ReadAll()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if records[1][1] != records[2][1] || records[1][1] == list[0].Phone {
+		t.Errorf("unexpected token: %v", records)
+	}
+
+	reader := NewClientReader(strings.NewReader(buf.String()), WithReaderKeyRing(ring))
+	reader.Read() //第一行表头不能处理成结构体，读取第一行
+
+	var result []testSecretBean
+	err = reader.ReadRowsFromFile(&result)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if result[0].IDNo != "110101199003071234" || result[1].IDNo != list[1].IDNo {
+		t.Errorf("unexpected data: %+v", result)
+	}
+
+	err = NewClientWriter(&bytes.Buffer{}).WriteRows2File(list)
+	if err == nil {
+		t.Error("tokenize without key should be an error")
+	}
+}
@@ -1,15 +1,57 @@
 package easy_csv
 
 import (
+	"bufio"
+	"bytes"
+	"context"
 	"encoding/csv"
 	"io"
 )
 
 // ClientReader a reader client is used to read and unmarshal file of csv
 type ClientReader struct {
-	r *csv.Reader
+	r          *csv.Reader
+	starts     *recordStartReader // finds the start of records after the skipped lines
+	option     *ClientReaderOption
+	width      int   // field count of the header,used by RaggedPolicy
+	offsetBase int64 // bytes of input before the csv.Reader
+	lineBase   int   // lines of input before the csv.Reader
+	progress   *progressTracker
+	rows       int      // records read
+	scanned    int      // records parsed,the rows rejected by RaggedPolicy included,used by MaxRows
+	header     []string // the first record
+	lines      int      // lines of input up to the end of the last record
 }
 
+// EmptyPolicy decides how an empty cell, or a cell missing from a short row, is unmarshalled
+type EmptyPolicy int
+
+const (
+	// EmptyDefault use the value of tag option `default=` if the field has one,
+	// otherwise an empty numeric cell is an error. It is the default policy.
+	EmptyDefault EmptyPolicy = iota
+	// EmptyError an empty numeric cell is always an error,the `default=` tag option is ignored
+	EmptyError
+	// EmptyZero an empty numeric cell is set to zero value,the `default=` tag option is ignored
+	EmptyZero
+)
+
+// RaggedPolicy decides how a row whose field count differs from the header is handled.
+// The header width is FieldsPerRecord if it is positive,otherwise the field count of the first record.
+type RaggedPolicy int
+
+const (
+	// RaggedStrict leave the check to FieldsPerRecord of encoding/csv. It is the default policy.
+	RaggedStrict RaggedPolicy = iota
+	// RaggedPad pad short rows with empty cells,the extra cells of long rows are kept
+	RaggedPad
+	// RaggedTruncate truncate long rows to the header width,short rows are kept
+	RaggedTruncate
+	// RaggedReject skip the rows and add them to the ErrorCollector,
+	// the read is aborted with a *RowError if the reader has no ErrorCollector
+	RaggedReject
+)
+
 type ClientReaderOption struct {
 	// Comma is the field delimiter.
 	// It is set to comma (',') by NewReader.
@@ -46,6 +88,38 @@ type ClientReaderOption struct {
 	// the backing array of the previous call's returned slice for performance.
 	// By default, each call to Read returns newly allocated memory owned by the caller.
 	ReuseRecord bool
+
+	// EmptyPolicy decides how empty cells and the missing trailing cells of short rows are unmarshalled.
+	// Only the cells of numeric fields can fail, the empty cells of other fields are set as is.
+	EmptyPolicy EmptyPolicy
+
+	// RaggedPolicy decides how a row whose field count differs from the header is handled.
+	// Any policy except RaggedStrict disables the field count check of FieldsPerRecord.
+	// The cells beyond the columns of a structure are captured by a []string field tagged `csv:",overflow"`.
+	RaggedPolicy RaggedPolicy
+
+	// ErrorCollector receives the rows rejected by the reader
+	ErrorCollector *ErrorCollector
+
+	// KeyRing decrypts the fields tagged 'encrypt',the encrypted cells are kept as is if it is nil
+	KeyRing *KeyRing
+
+	// Excel skip the UTF-8 BOM and the 'sep=' line,and unwrap the cells like ="0123"
+	Excel bool
+
+	// Progress reports the statistics while reading
+	Progress *ProgressOption
+
+	// MaxFieldSize the maximum bytes of a field,0 means no limit
+	MaxFieldSize int
+	// MaxFields the maximum fields of a record,0 means no limit
+	MaxFields int
+	// MaxRows the maximum records,the header and the rows rejected by RaggedPolicy are counted,0 means no limit
+	MaxRows int
+	// MaxBytes the maximum bytes of the input,0 means no limit.
+	// A *LimitError is returned when a limit is exceeded,
+	// the field size, the record width and the input size are checked while the input is read.
+	MaxBytes int64
 }
 
 type ClientReaderOptionFunc func(opt *ClientReaderOption)
@@ -57,10 +131,41 @@ func NewClientReader(reader io.Reader, opts ...ClientReaderOptionFunc) *ClientRe
 		o(option)
 	}
 
-	r := csv.NewReader(reader)
+	return newClientReader(reader, option, nil)
+}
+
+// newClientReader create a reader at the beginning of input,or at checkpoint if it is not nil
+func newClientReader(reader io.Reader, option *ClientReaderOption, checkpoint *Checkpoint) *ClientReader {
+	var offsetBase int64
+	var lineBase int
+	var sep rune
+	if checkpoint != nil {
+		offsetBase, lineBase, sep = checkpoint.Offset, checkpoint.Line, checkpoint.Comma
+	}
+
+	if option.Excel && checkpoint == nil {
+		br := bufio.NewReader(reader)
+		sep, offsetBase, lineBase = skipExcelPreamble(br)
+		reader = br
+	}
+
+	comma := ','
 	if option.Comma != 0 {
-		r.Comma = option.Comma
+		comma = option.Comma
+	} else if sep != 0 {
+		comma = sep
+	}
+
+	//限制检查在Excel前导行之后，使用最终的分隔符
+	if hasLimits(option) {
+		limit := newLimitReader(reader, option, comma)
+		limit.n, limit.line = offsetBase, lineBase+1
+		reader = limit
 	}
+
+	starts := newRecordStartReader(reader, option.Comment)
+	r := csv.NewReader(starts)
+	r.Comma = comma
 	if option.Comment != 0 {
 		r.Comment = option.Comment
 	}
@@ -70,9 +175,28 @@ func NewClientReader(reader io.Reader, opts ...ClientReaderOptionFunc) *ClientRe
 	r.TrimLeadingSpace = option.TrimLeadingSpace
 	r.ReuseRecord = option.ReuseRecord
 
-	return &ClientReader{
-		r: r,
+	width := 0
+	if option.RaggedPolicy != RaggedStrict {
+		r.FieldsPerRecord = -1
+		if option.FieldsPerRecord > 0 {
+			width = option.FieldsPerRecord
+		}
+	}
+
+	client := &ClientReader{
+		r:          r,
+		option:     option,
+		width:      width,
+		starts:     starts,
+		offsetBase: offsetBase,
+		lineBase:   lineBase,
+		lines:      lineBase,
+		progress:   newProgressTracker(option.Progress),
 	}
+	if checkpoint != nil {
+		client.resume(checkpoint)
+	}
+	return client
 }
 
 func WithReaderComma(comma rune) ClientReaderOptionFunc {
@@ -111,27 +235,174 @@ func WithReuseRecord(reuse bool) ClientReaderOptionFunc {
 	}
 }
 
+func WithReaderEmptyPolicy(policy EmptyPolicy) ClientReaderOptionFunc {
+	return func(opt *ClientReaderOption) {
+		opt.EmptyPolicy = policy
+	}
+}
+
+func WithReaderRaggedPolicy(policy RaggedPolicy) ClientReaderOptionFunc {
+	return func(opt *ClientReaderOption) {
+		opt.RaggedPolicy = policy
+	}
+}
+
+func WithReaderErrorCollector(collector *ErrorCollector) ClientReaderOptionFunc {
+	return func(opt *ClientReaderOption) {
+		opt.ErrorCollector = collector
+	}
+}
+
+func WithReaderKeyRing(ring *KeyRing) ClientReaderOptionFunc {
+	return func(opt *ClientReaderOption) {
+		opt.KeyRing = ring
+	}
+}
+
+// Stats return the statistics of the rows read so far
+func (reader *ClientReader) Stats() Stats {
+	return reader.progress.snapshot()
+}
+
 // Read Read one line at a time
 func (reader *ClientReader) Read() ([]string, error) {
-	return reader.r.Read()
+	record, _, err := reader.readRecord()
+	return record, err
 }
 
 // ReadAll Read all the remaining lines in sequence
 func (reader *ClientReader) ReadAll() ([][]string, error) {
-	return reader.r.ReadAll()
+	records, _, err := reader.readRecords()
+	return records, err
+}
+
+// readRecords read all the remaining records with their provenance
+func (reader *ClientReader) readRecords() ([][]string, []*rowMeta, error) {
+	return reader.readRecordsContext(context.Background())
+}
+
+// readRecordsContext read all the remaining records with their provenance,and stop if ctx is done
+func (reader *ClientReader) readRecordsContext(ctx context.Context) ([][]string, []*rowMeta, error) {
+	records := make([][]string, 0)
+	metas := make([]*rowMeta, 0)
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, nil, &StopError{Row: reader.rows + 1, Err: err}
+		}
+
+		record, meta, err := reader.readRecord()
+		if err == io.EOF {
+			return records, metas, nil
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+
+		//复用的记录会被下一次读取覆盖
+		if reader.option.ReuseRecord {
+			record = append([]string(nil), record...)
+			meta.raw = append([]string(nil), meta.raw...)
+		}
+		records = append(records, record)
+		metas = append(metas, meta)
+	}
+}
+
+// readRecord read the next record,check the limits and count it in the statistics
+func (reader *ClientReader) readRecord() ([]string, *rowMeta, error) {
+	record, meta, err := reader.nextRecord()
+	if err == nil {
+		reader.rows++
+		reader.mark(meta)
+		reader.progress.row(reader.offsetBase + reader.r.InputOffset())
+	}
+	return record, meta, err
+}
+
+// nextRecord read the next record and apply the RaggedPolicy,rejected rows are skipped
+func (reader *ClientReader) nextRecord() ([]string, *rowMeta, error) {
+	for {
+		record, err := reader.r.Read()
+		offset := reader.starts.start()
+		reader.starts.advance(reader.r.InputOffset())
+		if err != nil {
+			if err != io.EOF {
+				reader.progress.fail()
+			}
+			return record, nil, err
+		}
+
+		line, _ := reader.r.FieldPos(0)
+		meta := &rowMeta{
+			line:   reader.lineBase + line,
+			offset: reader.offsetBase + offset,
+			raw:    record,
+		}
+
+		err = reader.checkLimits(meta)
+		if err != nil {
+			reader.progress.fail()
+			return nil, nil, err
+		}
+
+		if reader.option.Excel {
+			record = unwrapExcelCells(record)
+		}
+
+		if reader.option.RaggedPolicy == RaggedStrict {
+			return record, meta, nil
+		}
+
+		//第一行决定表头宽度
+		if reader.width == 0 {
+			reader.width = len(record)
+			return record, meta, nil
+		}
+
+		if len(record) == reader.width {
+			return record, meta, nil
+		}
+
+		switch reader.option.RaggedPolicy {
+		case RaggedPad:
+			if len(record) < reader.width {
+				padded := make([]string, reader.width)
+				copy(padded, record)
+				record = padded
+			}
+		case RaggedTruncate:
+			if len(record) > reader.width {
+				record = record[:reader.width]
+			}
+		case RaggedReject:
+			rowErr := &RowError{
+				Line:   meta.line,
+				Record: append([]string(nil), record...),
+				Err:    csv.ErrFieldCount,
+			}
+			reader.progress.fail()
+			if reader.option.ErrorCollector == nil {
+				return nil, nil, rowErr
+			}
+			reader.option.ErrorCollector.add(rowErr)
+			continue
+		}
+		return record, meta, nil
+	}
 }
 
 // ReadRowFromFile Read a row of lines and parse it into each field of the structure in the order of columns.
 //
 // structure: The parameter structure is a structure pointer
 func (reader *ClientReader) ReadRowFromFile(structure interface{}) error {
-	row, err := reader.Read()
+	row, meta, err := reader.readRecord()
 	if err != nil {
 		return err
 	}
 
-	err = unmarshalOneDSlice(row, structure)
+	err = unmarshalOneDSlice(row, structure, reader.option, meta)
 	if err != nil {
+		reader.progress.fail()
 		return err
 	}
 	return nil
@@ -143,13 +414,14 @@ func (reader *ClientReader) ReadRowFromFile(structure interface{}) error {
 //
 // structure: The parameter structure is a structure pointer
 func (reader *ClientReader) ReadRowFromFileWithNames(names []string, structure interface{}) error {
-	row, err := reader.Read()
+	row, meta, err := reader.readRecord()
 	if err != nil {
 		return err
 	}
 
-	err = unmarshalOneDSliceWithNames(names, row, structure)
+	err = unmarshalOneDSliceWithNames(names, row, structure, reader.option, meta)
 	if err != nil {
+		reader.progress.fail()
 		return err
 	}
 	return nil
@@ -159,12 +431,13 @@ func (reader *ClientReader) ReadRowFromFileWithNames(names []string, structure i
 //
 // list: The parameter list is a list pointer,the item of list must be a structure or a structure pointer
 func (reader *ClientReader) ReadRowsFromFile(list interface{}) error {
-	rows, err := reader.ReadAll()
+	rows, metas, err := reader.readRecords()
 	if err != nil {
 		return err
 	}
-	err = unmarshalTwoDSlice(rows, list)
+	err = unmarshalTwoDSlice(rows, list, reader.option, metas)
 	if err != nil {
+		reader.progress.fail()
 		return err
 	}
 
@@ -177,14 +450,107 @@ func (reader *ClientReader) ReadRowsFromFile(list interface{}) error {
 //
 // list: The parameter list is a list pointer,the item of list must be a structure or a structure pointer
 func (reader *ClientReader) ReadRowsFromFileWithNames(names []string, list interface{}) error {
-	rows, err := reader.ReadAll()
+	rows, metas, err := reader.readRecords()
 	if err != nil {
 		return err
 	}
-	err = unmarshalTwoDSliceWithNames(names, rows, list)
+	err = unmarshalTwoDSliceWithNames(names, rows, list, reader.option, metas)
 	if err != nil {
+		reader.progress.fail()
 		return err
 	}
 
 	return nil
 }
+
+// recordStartReader keeps the bytes read by encoding/csv after the end of the last record,
+// and drops the empty lines and comment lines at their beginning,
+// so that the offset of the next record is known after encoding/csv skips those lines
+type recordStartReader struct {
+	r         io.Reader
+	comment   []byte
+	buf       []byte // bytes read after base
+	base      int64  // offset of buf[0] in the input of encoding/csv
+	found     bool   // buf starts at the next record
+	inComment bool   // buf starts inside a comment line
+}
+
+func newRecordStartReader(r io.Reader, comment rune) *recordStartReader {
+	s := &recordStartReader{r: r}
+	if comment != 0 {
+		s.comment = []byte(string(comment))
+	}
+	return s
+}
+
+func (s *recordStartReader) Read(p []byte) (int, error) {
+	n, err := s.r.Read(p)
+	if n > 0 {
+		s.buf = append(s.buf, p[:n]...)
+		s.skip()
+	}
+	return n, err
+}
+
+// start return the offset of the record read last
+func (s *recordStartReader) start() int64 {
+	return s.base
+}
+
+// advance drop the bytes before end,the offset after the record read last
+func (s *recordStartReader) advance(end int64) {
+	n := end - s.base
+	if n < 0 {
+		return
+	}
+	if n > int64(len(s.buf)) {
+		n = int64(len(s.buf))
+	}
+	s.buf = append(s.buf[:0], s.buf[n:]...)
+	s.base = end
+	s.found = false
+	s.inComment = false
+	s.skip()
+}
+
+// skip drop the complete empty lines and comment lines at the beginning of buf until the next record
+func (s *recordStartReader) skip() {
+	for !s.found && len(s.buf) > 0 {
+		if s.inComment {
+			i := bytes.IndexByte(s.buf, '\n')
+			if i < 0 {
+				s.drop(len(s.buf))
+				return
+			}
+			s.drop(i + 1)
+			s.inComment = false
+			continue
+		}
+
+		switch {
+		case s.buf[0] == '\n':
+			s.drop(1)
+		case s.buf[0] == '\r':
+			if len(s.buf) < 2 {
+				return
+			}
+			if s.buf[1] != '\n' {
+				s.found = true
+				return
+			}
+			s.drop(2)
+		case len(s.comment) > 0 && bytes.HasPrefix(s.buf, s.comment):
+			s.inComment = true
+		case len(s.comment) > 0 && bytes.HasPrefix(s.comment, s.buf):
+			//注释符还不完整
+			return
+		default:
+			s.found = true
+		}
+	}
+}
+
+func (s *recordStartReader) drop(n int) {
+	s.buf = s.buf[n:]
+	s.base += int64(n)
+}
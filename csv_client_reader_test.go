@@ -1,7 +1,9 @@
 package easy_csv
 
 import (
+	"errors"
 	"os"
+	"strings"
 	"testing"
 )
 
@@ -113,3 +115,152 @@ func TestClientReader_ReadRowsFromFileWithNames(t *testing.T) {
 	}
 	t.Logf("data:%+v\n", list)
 }
+
+func TestClientReader_ReadRowsFromFileEmptyPolicy(t *testing.T) {
+	data := "name,qty,price\napple,,1.5\npear,3,\n"
+
+	clientReader := NewClientReader(strings.NewReader(data), WithReaderEmptyPolicy(EmptyZero))
+
+	clientReader.Read() //第一行表头不能处理成结构体，读取第一行
+
+	var list []testDefaultBean
+	err := clientReader.ReadRowsFromFile(&list)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	if len(list) != 2 || list[0].Qty != 0 || list[1].Price != 0 {
+		t.Errorf("unexpected data:%+v", list)
+	}
+	t.Logf("data:%+v\n", list)
+}
+
+type testRaggedBean struct {
+	Name  string   `csv:"name"`
+	Age   int      `csv:"age,default=0"`
+	Extra []string `csv:",overflow"`
+}
+
+func TestClientReader_ReadRowsFromFileRagged(t *testing.T) {
+	data := "name,age\nbob,10\njam\nxeon,12,a,b\n"
+
+	//短行补齐，长行多出的列写入overflow字段
+	clientReader := NewClientReader(strings.NewReader(data), WithReaderRaggedPolicy(RaggedPad))
+	clientReader.Read() //第一行表头不能处理成结构体，读取第一行
+
+	var list []testRaggedBean
+	err := clientReader.ReadRowsFromFile(&list)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	if len(list) != 3 || len(list[2].Extra) != 2 || list[2].Extra[1] != "b" {
+		t.Errorf("unexpected data:%+v", list)
+	}
+	t.Logf("data:%+v\n", list)
+
+	//截断长行
+	clientReader = NewClientReader(strings.NewReader(data), WithReaderRaggedPolicy(RaggedTruncate))
+	clientReader.Read()
+
+	list = nil
+	err = clientReader.ReadRowsFromFile(&list)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	if len(list) != 3 || len(list[2].Extra) != 0 {
+		t.Errorf("unexpected data:%+v", list)
+	}
+
+	//拒绝不等宽的行
+	collector := NewErrorCollector()
+	clientReader = NewClientReader(strings.NewReader(data), WithReaderRaggedPolicy(RaggedReject), WithReaderErrorCollector(collector))
+	clientReader.Read()
+
+	list = nil
+	err = clientReader.ReadRowsFromFile(&list)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	if len(list) != 1 || collector.Len() != 2 || collector.Errors()[0].Line != 3 {
+		t.Errorf("unexpected data:%+v, errors:%v", list, collector.Errors())
+	}
+
+	//没有错误收集器时中止读取
+	clientReader = NewClientReader(strings.NewReader(data), WithReaderRaggedPolicy(RaggedReject))
+	clientReader.Read()
+
+	list = nil
+	err = clientReader.ReadRowsFromFile(&list)
+	var rowErr *RowError
+	if !errors.As(err, &rowErr) {
+		t.Errorf("expected a row error, got %v", err)
+	}
+}
+
+type testMetaBean struct {
+	Name   string   `csv:"name"`
+	Note   string   `csv:"note"`
+	Line   int      `csv:",line"`
+	Offset int64    `csv:",offset"`
+	Raw    []string `csv:",raw"`
+}
+
+func TestClientReader_ReadRowsFromFileMeta(t *testing.T) {
+	data := "name,note\nbob,\"two\nlines\"\njam,one\n"
+
+	clientReader := NewClientReader(strings.NewReader(data))
+	clientReader.Read() //第一行表头不能处理成结构体，读取第一行
+
+	row := testMetaBean{}
+	err := clientReader.ReadRowFromFile(&row)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	if row.Line != 2 || row.Offset != 10 || len(row.Raw) != 2 {
+		t.Errorf("unexpected data:%+v", row)
+	}
+
+	var list []*testMetaBean
+	err = clientReader.ReadRowsFromFileWithNames([]string{"Name", "Note"}, &list)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	if len(list) != 1 || list[0].Line != 4 || list[0].Offset != int64(strings.Index(data, "jam")) || list[0].Raw[0] != "jam" {
+		t.Errorf("unexpected data:%+v", list[0])
+	}
+	t.Logf("data:%+v %+v\n", row, list[0])
+}
+
+func TestClientReader_MetaOffsetSkippedLines(t *testing.T) {
+	data := "name,note\n\n\r\nbob,1\n#c,\"x\n\njam,2\n"
+
+	clientReader := NewClientReader(strings.NewReader(data), WithReaderComment('#'))
+	clientReader.Read()
+
+	//偏移量是记录的开头，跳过空行和注释行
+	var list []testMetaBean
+	err := clientReader.ReadRowsFromFile(&list)
+	if err != nil {
+		t.Error(err.Error())
+		return
+	}
+	if len(list) != 2 || list[0].Offset != int64(strings.Index(data, "bob")) || list[1].Offset != int64(strings.Index(data, "jam")) {
+		t.Errorf("unexpected data:%+v", list)
+	}
+	if list[1].Line != 7 {
+		t.Errorf("unexpected line:%d", list[1].Line)
+	}
+
+	clientReader = NewClientReader(strings.NewReader("name,qty\n\n\na,1\n"))
+	clientReader.Read()
+	row := testMetaBean{}
+	err = clientReader.ReadRowFromFile(&row)
+	if err != nil || row.Offset != 11 {
+		t.Errorf("unexpected data:%+v %v", row, err)
+	}
+}
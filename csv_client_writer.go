@@ -3,16 +3,45 @@ package easy_csv
 import (
 	"encoding/csv"
 	"io"
+	"reflect"
 )
 
 // ClientWriter a writer client is used to write data to csv
 type ClientWriter struct {
-	w *csv.Writer
+	w        *csv.Writer
+	out      io.Writer
+	option   *ClientWriterOption
+	header   []string // column names,used to find the numeric columns of formula escaping
+	started  bool     // anything has been written
+	counter  *countingWriter
+	progress *progressTracker
 }
 
 type ClientWriterOption struct {
-	Comma   rune // Field delimiter (cloud set to ',')
-	UseCRLF bool // True to use \r\n as the line terminator
+	Comma    rune     // Field delimiter (cloud set to ',')
+	UseCRLF  bool     // True to use \r\n as the line terminator
+	TokenKey []byte   // HMAC key of the fields tagged 'tokenize'
+	KeyRing  *KeyRing // AES keys of the fields tagged 'encrypt'
+
+	// MaskPolicy overrides the maskers declared in struct tags,nil means the tags decide
+	MaskPolicy *MaskPolicy
+
+	// Columns the fields written as columns in order,all columns of structure are written if it is empty
+	Columns []ColumnSpec
+
+	// EscapeFormula prefix the cells starting with '=', '+', '-', '@', tab or carriage return with a single quote
+	EscapeFormula bool
+	// FormulaNumericColumns names of columns whose numeric values are not escaped,
+	// the columns are found in the header of structure,or in the first row written by WriteString2File
+	FormulaNumericColumns []string
+
+	// Excel write a UTF-8 BOM before the first record and protect the numbers Excel would change
+	Excel bool
+	// ExcelSepLine write a 'sep=' line after the BOM
+	ExcelSepLine bool
+
+	// Progress reports the statistics while writing
+	Progress *ProgressOption
 }
 
 type ClientWriterOptionFunc func(*ClientWriterOption)
@@ -23,14 +52,21 @@ func NewClientWriter(writer io.Writer, opts ...ClientWriterOptionFunc) *ClientWr
 		opt(option)
 	}
 
-	w := csv.NewWriter(writer)
+	counter := &countingWriter{w: writer}
+	w := csv.NewWriter(counter)
 	if option.Comma != 0 {
 		w.Comma = option.Comma
 	}
 
 	w.UseCRLF = option.UseCRLF
 
-	return &ClientWriter{w: w}
+	return &ClientWriter{
+		w:        w,
+		out:      counter,
+		option:   option,
+		counter:  counter,
+		progress: newProgressTracker(option.Progress),
+	}
 }
 
 func WithWriterComma(comma rune) ClientWriterOptionFunc {
@@ -45,6 +81,29 @@ func WithWriterUseCRLF(useCRLF bool) ClientWriterOptionFunc {
 	}
 }
 
+// WithWriterTokenKey set the HMAC key of the fields tagged 'tokenize',
+// the same value is always written as the same token with the same key
+func WithWriterTokenKey(key []byte) ClientWriterOptionFunc {
+	return func(opt *ClientWriterOption) {
+		opt.TokenKey = key
+	}
+}
+
+// WithWriterKeyRing set the AES keys of the fields tagged 'encrypt'
+func WithWriterKeyRing(ring *KeyRing) ClientWriterOptionFunc {
+	return func(opt *ClientWriterOption) {
+		opt.KeyRing = ring
+	}
+}
+
+// Stats return the statistics of the rows written so far,
+// the bytes are counted when they are flushed to the underlying io.Writer
+func (writer *ClientWriter) Stats() Stats {
+	stats := writer.progress.snapshot()
+	stats.Bytes = writer.counter.n
+	return stats
+}
+
 // WriteRow2File Write a line of data to a file
 //
 // structure: The parameter data is a structure pointer
@@ -54,15 +113,7 @@ func (writer *ClientWriter) WriteRow2File(structure interface{}, setTitle ...boo
 		flag = setTitle[0]
 	}
 
-	records, err := marshalStructure(structure, flag)
-	if err != nil {
-		return err
-	}
-	err = writer.WriteString2File(records)
-	if err != nil {
-		return err
-	}
-	return nil
+	return writer.writeStructure(structure, flag, writer.option)
 }
 
 // WriteRows2File Write multiple lines of data to a file
@@ -74,25 +125,104 @@ func (writer *ClientWriter) WriteRows2File(list interface{}, setTitle ...bool) e
 		flag = setTitle[0]
 	}
 
-	records, err := marshalList(list, flag)
+	return writer.writeList(list, flag, writer.option)
+}
+
+// writeStructure marshal a structure with option and write it
+func (writer *ClientWriter) writeStructure(structure interface{}, setTitle bool, option *ClientWriterOption) error {
+	records, err := marshalStructure(structure, setTitle, option)
 	if err != nil {
 		return err
 	}
-	err = writer.WriteString2File(records)
+	writer.setHeader(structure, option)
+	return writer.WriteString2File(records)
+}
+
+// writeList marshal a list with option and write it
+func (writer *ClientWriter) writeList(list interface{}, setTitle bool, option *ClientWriterOption) error {
+	records, err := marshalList(list, setTitle, option)
 	if err != nil {
 		return err
 	}
-	return nil
+	writer.setHeader(list, option)
+	return writer.WriteString2File(records)
 }
 
 // WriteString2File 向CSV文件中写入文本数据
 //
 // data 切片每个元素代表一行，每行元素还是一个切片，其中每个元素代表一列
 func (writer *ClientWriter) WriteString2File(data [][]string) error {
-	err := writer.w.WriteAll(data)
+	err := writer.writeRecords(data)
 	if err != nil {
 		return err
 	}
+	return writer.Flush()
+}
+
+// Flush write any buffered data to the underlying io.Writer,and report the error of any previous write or flush
+func (writer *ClientWriter) Flush() error {
 	writer.w.Flush()
+	err := writer.w.Error()
+	if err != nil {
+		writer.progress.fail()
+	}
+	return err
+}
+
+// writeRecords write records to the buffer of writer without flushing
+func (writer *ClientWriter) writeRecords(data [][]string) error {
+	if writer.option.EscapeFormula {
+		//没有结构体表头时，第一行作为表头
+		if writer.header == nil && len(data) > 0 {
+			writer.header = data[0]
+		}
+		data = escapeFormulas(data, writer.header, writer.option.FormulaNumericColumns)
+	}
+
+	if writer.option.Excel {
+		data = protectExcelCells(data)
+	}
+
+	err := writer.start()
+	if err != nil {
+		return err
+	}
+
+	for _, record := range data {
+		err = writer.w.Write(record)
+		if err != nil {
+			writer.progress.fail()
+			return err
+		}
+		writer.progress.row(writer.counter.n)
+	}
 	return nil
 }
+
+// setTableHeader remember the column names of a Table for formula escaping
+func (writer *ClientWriter) setTableHeader(header []string) {
+	if writer.option.EscapeFormula {
+		writer.header = header
+	}
+}
+
+// setHeader remember the column names of a structure or a list for formula escaping
+func (writer *ClientWriter) setHeader(v interface{}, option *ClientWriterOption) {
+	if writer.option.EscapeFormula {
+		writer.header = structureTitle(reflect.TypeOf(v), option)
+	}
+}
+
+// start write the BOM and 'sep=' line of Excel before the first record
+func (writer *ClientWriter) start() error {
+	if writer.started {
+		return nil
+	}
+	writer.started = true
+
+	if !writer.option.Excel {
+		return nil
+	}
+	_, err := io.WriteString(writer.out, excelPreamble(writer.option, writer.w.Comma))
+	return err
+}
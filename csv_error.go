@@ -0,0 +1,55 @@
+package easy_csv
+
+import (
+	"fmt"
+	"sync"
+)
+
+// RowError is the error of a row rejected by the reader
+type RowError struct {
+	Line   int      // line number of the row,starting at 1
+	Record []string // fields of the rejected row
+	Err    error
+}
+
+func (e *RowError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+func (e *RowError) Unwrap() error {
+	return e.Err
+}
+
+// ErrorCollector collects rows rejected by ClientReader so that a read can go on instead of being aborted.
+// It is safe for concurrent use.
+type ErrorCollector struct {
+	mu   sync.Mutex
+	errs []*RowError
+}
+
+func NewErrorCollector() *ErrorCollector {
+	return &ErrorCollector{}
+}
+
+// Errors return all collected errors in the order they were added
+func (c *ErrorCollector) Errors() []*RowError {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	errs := make([]*RowError, len(c.errs))
+	copy(errs, c.errs)
+	return errs
+}
+
+// Len return the number of collected errors
+func (c *ErrorCollector) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.errs)
+}
+
+func (c *ErrorCollector) add(err *RowError) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.errs = append(c.errs, err)
+}
@@ -0,0 +1,23 @@
+package easy_csv
+
+import (
+	"encoding/csv"
+	"errors"
+	"testing"
+)
+
+func TestErrorCollector(t *testing.T) {
+	collector := NewErrorCollector()
+	collector.add(&RowError{Line: 3, Record: []string{"a"}, Err: csv.ErrFieldCount})
+
+	if collector.Len() != 1 {
+		t.Errorf("unexpected error count: %d", collector.Len())
+		return
+	}
+
+	err := collector.Errors()[0]
+	if !errors.Is(err, csv.ErrFieldCount) {
+		t.Errorf("row error should wrap csv.ErrFieldCount: %v", err)
+	}
+	t.Logf("error: %v\n", err)
+}
@@ -0,0 +1,249 @@
+package easy_csv
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// ErrEncoderClosed is returned by the methods of a closed Encoder
+var ErrEncoderClosed = errors.New("easy_csv: encoder is closed")
+
+// Encoder writes structures to a ClientWriter one at a time.
+// The header is written once,lazily before the first row,and rows are buffered until a flush.
+// An Encoder is meant to be used by one goroutine,its methods only lock against the timer of FlushInterval,
+// and the ClientWriter must not be used directly until the Encoder is closed.
+type Encoder struct {
+	mu        sync.Mutex
+	writer    *ClientWriter
+	option    *EncoderOption
+	wroteHead bool
+	pending   int // rows written since the last flush
+	lastFlush time.Time
+	timer     *time.Timer // flushes the pending rows after FlushInterval,nil if none is pending
+	closed    bool
+	err       error // the first error,every later call returns it
+}
+
+type EncoderOption struct {
+	// Header write the header before the first row,it is true by default
+	Header bool
+
+	// FlushRows flush after this many rows are buffered,0 means no limit
+	FlushRows int
+
+	// FlushInterval flush the buffered rows at most this long after they are written,0 means no limit.
+	// An idle Encoder is flushed by a timer,the error of a timer flush is returned by the next call.
+	FlushInterval time.Duration
+}
+
+type EncoderOptionFunc func(opt *EncoderOption)
+
+func WithEncoderHeader(header bool) EncoderOptionFunc {
+	return func(opt *EncoderOption) {
+		opt.Header = header
+	}
+}
+
+func WithEncoderFlushRows(rows int) EncoderOptionFunc {
+	return func(opt *EncoderOption) {
+		opt.FlushRows = rows
+	}
+}
+
+func WithEncoderFlushInterval(interval time.Duration) EncoderOptionFunc {
+	return func(opt *EncoderOption) {
+		opt.FlushInterval = interval
+	}
+}
+
+// NewEncoder create an Encoder writing to writer
+func (writer *ClientWriter) NewEncoder(opts ...EncoderOptionFunc) *Encoder {
+	option := &EncoderOption{
+		Header: true,
+	}
+	for _, o := range opts {
+		o(option)
+	}
+
+	return &Encoder{
+		writer:    writer,
+		option:    option,
+		lastFlush: time.Now(),
+	}
+}
+
+// Encode write a row
+//
+// structure: a structure or a structure pointer
+func (enc *Encoder) Encode(structure interface{}) error {
+	enc.mu.Lock()
+	defer enc.mu.Unlock()
+
+	if err := enc.check(); err != nil {
+		return err
+	}
+
+	records, err := marshalStructure(structure, enc.needHeader(), enc.writer.option)
+	if err != nil {
+		return err
+	}
+	return enc.write(structure, records)
+}
+
+// EncodeAll write every item of list.
+// The header is written even if list is empty,so that an empty export is a header-only file.
+//
+// list: a list or a list pointer,the item of list must be a structure or a structure pointer
+func (enc *Encoder) EncodeAll(list interface{}) error {
+	enc.mu.Lock()
+	defer enc.mu.Unlock()
+
+	if err := enc.check(); err != nil {
+		return err
+	}
+
+	records, err := marshalList(list, enc.needHeader(), enc.writer.option)
+	if err != nil {
+		return err
+	}
+	if len(records) == 0 && enc.needHeader() {
+		return enc.writeHeader(list)
+	}
+	return enc.write(list, records)
+}
+
+// WriteHeader write the header of a structure type if it has not been written,
+// v can be a structure,a structure pointer,or a list of them,even a nil pointer or an empty list
+func (enc *Encoder) WriteHeader(v interface{}) error {
+	enc.mu.Lock()
+	defer enc.mu.Unlock()
+
+	if err := enc.check(); err != nil {
+		return err
+	}
+	return enc.writeHeader(v)
+}
+
+func (enc *Encoder) writeHeader(v interface{}) error {
+	if enc.wroteHead {
+		return nil
+	}
+
+	title := structureTitle(reflect.TypeOf(v), enc.writer.option)
+	if title == nil {
+		return errors.New("header needs a structure type")
+	}
+	return enc.write(v, [][]string{title})
+}
+
+// Flush write the buffered rows to the underlying io.Writer and report any write error
+func (enc *Encoder) Flush() error {
+	enc.mu.Lock()
+	defer enc.mu.Unlock()
+
+	if enc.err != nil {
+		return enc.err
+	}
+	if enc.closed {
+		return ErrEncoderClosed
+	}
+	return enc.flush()
+}
+
+// Close flush the buffered rows,the Encoder can not be used after Close.
+// The underlying io.Writer is not closed.
+func (enc *Encoder) Close() error {
+	enc.mu.Lock()
+	defer enc.mu.Unlock()
+
+	if enc.closed {
+		return enc.err
+	}
+	if enc.err == nil {
+		enc.flush()
+	}
+	if enc.timer != nil {
+		enc.timer.Stop()
+		enc.timer = nil
+	}
+	enc.closed = true
+	return enc.err
+}
+
+func (enc *Encoder) check() error {
+	if enc.err != nil {
+		return enc.err
+	}
+	if enc.closed {
+		return ErrEncoderClosed
+	}
+	return nil
+}
+
+func (enc *Encoder) needHeader() bool {
+	return enc.option.Header && !enc.wroteHead
+}
+
+// write buffer the records and flush if a limit is reached
+func (enc *Encoder) write(v interface{}, records [][]string) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	enc.writer.setHeader(v, enc.writer.option)
+	err := enc.writer.writeRecords(records)
+	if err != nil {
+		enc.err = err
+		return err
+	}
+
+	rows := len(records)
+	if enc.needHeader() {
+		enc.wroteHead = true
+		rows--
+	}
+	enc.pending += rows
+
+	if enc.option.FlushRows > 0 && enc.pending >= enc.option.FlushRows {
+		return enc.flush()
+	}
+	if enc.option.FlushInterval > 0 {
+		if time.Since(enc.lastFlush) >= enc.option.FlushInterval {
+			return enc.flush()
+		}
+		if enc.timer == nil && enc.pending > 0 {
+			enc.timer = time.AfterFunc(enc.option.FlushInterval, enc.flushOnTimer)
+		}
+	}
+	return nil
+}
+
+// flushOnTimer flush the rows pending longer than FlushInterval
+func (enc *Encoder) flushOnTimer() {
+	enc.mu.Lock()
+	defer enc.mu.Unlock()
+
+	enc.timer = nil
+	if enc.closed || enc.err != nil || enc.pending == 0 {
+		return
+	}
+	enc.flush()
+}
+
+func (enc *Encoder) flush() error {
+	if enc.timer != nil {
+		enc.timer.Stop()
+		enc.timer = nil
+	}
+
+	err := enc.writer.Flush()
+	if err != nil {
+		enc.err = err
+		return err
+	}
+	enc.pending = 0
+	enc.lastFlush = time.Now()
+	return nil
+}
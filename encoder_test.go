@@ -0,0 +1,148 @@
+package easy_csv
+
+import (
+	"bytes"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+type testFailWriter struct{}
+
+func (testFailWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+func TestEncoder(t *testing.T) {
+	buf := &bytes.Buffer{}
+	enc := NewClientWriter(buf).NewEncoder(WithEncoderFlushRows(2))
+
+	list := []testStudentInfo{
+		{Name: "王五", Age: 12, Grade: "6年级", Score: 123.01, Email: "wangwu@qq.com", Phone: "133111"},
+		{Name: "张三", Age: 11, Grade: "5年级", Score: 123.02, Email: "zh@qq.com", Phone: "13322225559"},
+	}
+
+	err := enc.Encode(list[0])
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if buf.Len() != 0 {
+		t.Errorf("row should be buffered: %s", buf.String())
+	}
+
+	err = enc.Encode(&list[1])
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	expect := "name,Age,Grade,分数,邮箱,手机号\n" +
+		"王五,12,6年级,123.01,wa***u@qq.com,1****1\n" +
+		"张三,11,5年级,123.02,zh@qq.com,133****5559\n"
+	if buf.String() != expect {
+		t.Errorf("unexpected data:\n%s", buf.String())
+	}
+
+	err = enc.EncodeAll(list)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	err = enc.Close()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if bytes.Count(buf.Bytes(), []byte("name,")) != 1 {
+		t.Errorf("header should be written once:\n%s", buf.String())
+	}
+	if enc.Encode(list[0]) != ErrEncoderClosed {
+		t.Error("closed encoder should return ErrEncoderClosed")
+	}
+	t.Logf("data:\n%s", buf.String())
+}
+
+func TestEncoder_EncodeAllEmpty(t *testing.T) {
+	buf := &bytes.Buffer{}
+	enc := NewClientWriter(buf).NewEncoder()
+
+	err := enc.EncodeAll([]*testStudentInfo{})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	err = enc.Close()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if buf.String() != "name,Age,Grade,分数,邮箱,手机号\n" {
+		t.Errorf("unexpected data:\n%s", buf.String())
+	}
+}
+
+func TestEncoder_Error(t *testing.T) {
+	enc := NewClientWriter(testFailWriter{}).NewEncoder()
+
+	err := enc.Encode(testStudentInfo{Name: "王五"})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	err = enc.Close()
+	if err == nil {
+		t.Error("write error should be returned by Close")
+	}
+
+	err = NewClientWriter(testFailWriter{}).WriteString2File([][]string{{"a"}})
+	if err == nil {
+		t.Error("write error should be returned by WriteString2File")
+	}
+}
+
+// testSyncBuffer is a bytes.Buffer safe for concurrent use
+type testSyncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *testSyncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *testSyncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestEncoder_FlushIntervalIdle(t *testing.T) {
+	buf := &testSyncBuffer{}
+	enc := NewClientWriter(buf).NewEncoder(WithEncoderFlushInterval(20 * time.Millisecond))
+
+	err := enc.Encode(testStudentInfo{Name: "王五", Age: 12})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if buf.String() != "" {
+		t.Errorf("rows should be buffered: %q", buf.String())
+	}
+
+	//空闲的Encoder由定时器刷新
+	deadline := time.Now().Add(2 * time.Second)
+	for buf.String() == "" && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if buf.String() != "name,Age,Grade,分数,邮箱,手机号\n王五,12,,0,,\n" {
+		t.Errorf("unexpected data: %q", buf.String())
+	}
+
+	err = enc.Close()
+	if err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,133 @@
+package easy_csv
+
+import (
+	"bufio"
+	"strings"
+	"unicode/utf8"
+)
+
+const utf8BOM = "\xef\xbb\xbf"
+
+// excelLongDigits the numbers of more than 10 digits,like phone numbers and ID cards,are kept as text for Excel.
+// Excel shows the numbers of 12 digits or more in scientific notation and drops the digits after the 15th.
+// 11-digit numbers are wrapped too: they are mobile phone numbers in China,which are identifiers rather than quantities,
+// and Excel also shows them in scientific notation in a column narrower than the number.
+const excelLongDigits = 10
+
+// WithWriterExcel write a file which Excel opens correctly:
+// a UTF-8 BOM so that non-ASCII headers are not garbled,CRLF line endings,
+// and the numbers with leading zeros or more than 10 digits wrapped as ="0123" so that they are kept as text.
+//
+// sepLine: true to write a 'sep=,' line after the BOM so that Excel uses the comma of writer
+func WithWriterExcel(sepLine bool) ClientWriterOptionFunc {
+	return func(opt *ClientWriterOption) {
+		opt.Excel = true
+		opt.ExcelSepLine = sepLine
+		opt.UseCRLF = true
+	}
+}
+
+// WithReaderExcel read a file written for Excel:
+// the UTF-8 BOM and the 'sep=' line are skipped,the comma of 'sep=' line is used unless the reader has a comma,
+// and the cells like ="0123" are unwrapped
+func WithReaderExcel() ClientReaderOptionFunc {
+	return func(opt *ClientReaderOption) {
+		opt.Excel = true
+	}
+}
+
+// excelPreamble return the BOM and the 'sep=' line written before the first record
+func excelPreamble(option *ClientWriterOption, comma rune) string {
+	preamble := utf8BOM
+	if option.ExcelSepLine {
+		preamble += "sep=" + string(comma) + "\r\n"
+	}
+	return preamble
+}
+
+// protectExcelCells return the records with the cells Excel would turn into numbers wrapped,data is not modified
+func protectExcelCells(data [][]string) [][]string {
+	protected := make([][]string, len(data))
+	for i, row := range data {
+		var protectedRow []string
+		for j, cell := range row {
+			if !isExcelFragileNumber(cell) {
+				continue
+			}
+			if protectedRow == nil {
+				protectedRow = append([]string(nil), row...)
+			}
+			protectedRow[j] = `="` + cell + `"`
+		}
+
+		if protectedRow == nil {
+			protectedRow = row
+		}
+		protected[i] = protectedRow
+	}
+	return protected
+}
+
+// isExcelFragileNumber report whether Excel would lose the leading zeros or the last digits of a cell
+func isExcelFragileNumber(cell string) bool {
+	if len(cell) < 2 {
+		return false
+	}
+	for i := 0; i < len(cell); i++ {
+		if cell[i] < '0' || cell[i] > '9' {
+			return false
+		}
+	}
+	return cell[0] == '0' || len(cell) > excelLongDigits
+}
+
+// unwrapExcelCells return the record with the cells like ="0123" unwrapped,record is not modified
+func unwrapExcelCells(record []string) []string {
+	var unwrapped []string
+	for i, cell := range record {
+		if len(cell) < 3 || !strings.HasPrefix(cell, `="`) || !strings.HasSuffix(cell, `"`) {
+			continue
+		}
+		if unwrapped == nil {
+			unwrapped = append([]string(nil), record...)
+		}
+		unwrapped[i] = strings.ReplaceAll(cell[2:len(cell)-1], `""`, `"`)
+	}
+
+	if unwrapped == nil {
+		return record
+	}
+	return unwrapped
+}
+
+// skipExcelPreamble skip the BOM and the 'sep=' line at the beginning of br.
+// It returns the comma of 'sep=' line,0 if there is none,and the bytes and lines skipped.
+func skipExcelPreamble(br *bufio.Reader) (comma rune, skipped int64, lines int) {
+	if bom, err := br.Peek(len(utf8BOM)); err == nil && string(bom) == utf8BOM {
+		br.Discard(len(utf8BOM))
+		skipped += int64(len(utf8BOM))
+	}
+
+	//sep=行很短，只检查开头的几个字节
+	line, err := br.Peek(16)
+	if !strings.HasPrefix(string(line), "sep=") {
+		return 0, skipped, 0
+	}
+
+	n := strings.IndexByte(string(line), '\n') + 1
+	if n == 0 {
+		if err == nil {
+			return 0, skipped, 0
+		}
+		n = len(line)
+	}
+
+	sep := strings.TrimRight(string(line[len("sep="):n]), "\r\n")
+	r, size := utf8.DecodeRuneInString(sep)
+	if size == 0 || size != len(sep) {
+		return 0, skipped, 0
+	}
+
+	br.Discard(n)
+	return r, skipped + int64(n), 1
+}
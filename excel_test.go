@@ -0,0 +1,82 @@
+package easy_csv
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+type testExcelBean struct {
+	Name  string `csv:"姓名"`
+	Phone string `csv:"手机号"`
+	Zip   string `csv:"邮编"`
+	Line  int    `csv:",line"`
+}
+
+func TestClientWriter_Excel(t *testing.T) {
+	buf := &bytes.Buffer{}
+	writer := NewClientWriter(buf, WithWriterExcel(true), WithWriterComma(';'))
+
+	list := []testExcelBean{
+		{Name: "王五", Phone: "13322226666", Zip: "010020"},
+		{Name: "张三", Phone: "1331111", Zip: "100000"},
+	}
+	err := writer.WriteRows2File(list, true)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	expect := utf8BOM + "sep=;\r\n" +
+		"姓名;手机号;邮编\r\n" +
+		"王五;\"=\"\"13322226666\"\"\";\"=\"\"010020\"\"\"\r\n" +
+		"张三;1331111;100000\r\n"
+	if buf.String() != expect {
+		t.Errorf("unexpected data:\n%q", buf.String())
+		return
+	}
+
+	reader := NewClientReader(bytes.NewReader(buf.Bytes()), WithReaderExcel())
+	header, err := reader.Read()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if header[0] != "姓名" {
+		t.Errorf("unexpected header: %q", header)
+	}
+
+	var result []testExcelBean
+	err = reader.ReadRowsFromFile(&result)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if len(result) != 2 || result[0].Phone != "13322226666" || result[0].Zip != "010020" || result[0].Line != 3 {
+		t.Errorf("unexpected data: %+v", result)
+	}
+	t.Logf("data: %+v\n", result)
+}
+
+func TestSkipExcelPreamble(t *testing.T) {
+	cases := map[string]string{
+		utf8BOM + "a,b\n":    "a,b\n",
+		"sep=\t\na\tb\n":     "a\tb\n",
+		"sep=;":              "",
+		"sepal,width\n1,2\n": "sepal,width\n1,2\n",
+	}
+	for input, expect := range cases {
+		reader := NewClientReader(strings.NewReader(input), WithReaderExcel())
+		records, err := reader.ReadAll()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		got := &strings.Builder{}
+		writer := NewClientWriter(got, WithWriterComma(reader.r.Comma))
+		_ = writer.WriteString2File(records)
+		if got.String() != expect {
+			t.Errorf("%q: expect %q, got %q", input, expect, got.String())
+		}
+	}
+}
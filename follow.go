@@ -0,0 +1,252 @@
+package easy_csv
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"time"
+)
+
+const defaultPollInterval = 200 * time.Millisecond
+
+// followChunkSize the bytes read from the file before the complete records are decoded
+const followChunkSize = 32 * 1024
+
+type FollowOption struct {
+	// PollInterval how often the file is checked for new records,200ms by default or if it is not positive
+	PollInterval time.Duration
+
+	// Header the first record of the file is a header and is not decoded,it is true by default.
+	// A truncated or rotated file starts with a header again.
+	Header bool
+
+	// ReaderOptions options of the reader decoding the records
+	ReaderOptions []ClientReaderOptionFunc
+}
+
+type FollowOptionFunc func(opt *FollowOption)
+
+func WithFollowPollInterval(interval time.Duration) FollowOptionFunc {
+	return func(opt *FollowOption) {
+		opt.PollInterval = interval
+	}
+}
+
+func WithFollowHeader(header bool) FollowOptionFunc {
+	return func(opt *FollowOption) {
+		opt.Header = header
+	}
+}
+
+func WithFollowReaderOptions(opts ...ClientReaderOptionFunc) FollowOptionFunc {
+	return func(opt *FollowOption) {
+		opt.ReaderOptions = opts
+	}
+}
+
+// Follow read the csv file at path like 'tail -f',and call fn with every record as it is appended.
+// A record is decoded once its line terminator is written,a partial trailing line is kept until it is complete.
+// When the file is truncated it is read again from the beginning,
+// and when another file is created at path the rest of the old file is read before switching to the new one.
+//
+// Follow returns ctx.Err() when ctx is done,or the first error of decoding a record or of fn.
+func Follow[T any](ctx context.Context, path string, fn func(row *T) error, opts ...FollowOptionFunc) error {
+	option := &FollowOption{
+		PollInterval: defaultPollInterval,
+		Header:       true,
+	}
+	for _, o := range opts {
+		o(option)
+	}
+	if option.PollInterval <= 0 {
+		option.PollInterval = defaultPollInterval
+	}
+	readerOption := &ClientReaderOption{}
+	for _, o := range option.ReaderOptions {
+		o(readerOption)
+	}
+
+	f := &follower[T]{path: path, option: option, readerOption: readerOption, fn: fn}
+	err := f.open()
+	if err != nil {
+		return err
+	}
+	defer f.file.Close()
+
+	ticker := time.NewTicker(option.PollInterval)
+	defer ticker.Stop()
+	for {
+		err = f.poll(ctx)
+		if err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// follower is the state of Follow on one file
+type follower[T any] struct {
+	path         string
+	option       *FollowOption
+	readerOption *ClientReaderOption
+	fn           func(row *T) error
+
+	file       *os.File
+	info       os.FileInfo
+	size       int64       // bytes read from file
+	pending    []byte      // bytes read but not decoded,starting at a record boundary
+	buf        []byte      // buffer of readChunk
+	checkpoint *Checkpoint // position after the last decoded record,nil at the beginning of file
+	headerSeen bool
+}
+
+func (f *follower[T]) open() error {
+	file, err := os.Open(f.path)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	f.file, f.info = file, info
+	f.reset()
+	return nil
+}
+
+// reset start reading file from the beginning
+func (f *follower[T]) reset() {
+	f.size = 0
+	f.pending = f.pending[:0]
+	f.checkpoint = nil
+	f.headerSeen = false
+}
+
+// poll decode the records appended since the last poll,and handle truncation and rotation
+func (f *follower[T]) poll(ctx context.Context) error {
+	//轮转前先读完旧文件
+	rotated := f.rotated()
+
+	//每次读取一块并立即解码，pending只保留末尾不完整的记录
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		n, err := f.readChunk()
+		if err != nil {
+			return err
+		}
+		err = f.decode(ctx)
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			break
+		}
+	}
+
+	if rotated {
+		f.file.Close()
+		return f.open()
+	}
+
+	info, err := f.file.Stat()
+	if err != nil {
+		return err
+	}
+	if info.Size() < f.size {
+		_, err = f.file.Seek(0, io.SeekStart)
+		if err != nil {
+			return err
+		}
+		f.reset()
+	}
+	return nil
+}
+
+// rotated report whether another file has been created at path,
+// a missing path is not a rotation as the new file may not be created yet
+func (f *follower[T]) rotated() bool {
+	info, err := os.Stat(f.path)
+	return err == nil && !os.SameFile(f.info, info)
+}
+
+// readChunk read at most followChunkSize bytes appended to file,0 at the end of file
+func (f *follower[T]) readChunk() (int, error) {
+	if f.buf == nil {
+		f.buf = make([]byte, followChunkSize)
+	}
+	n, err := f.file.Read(f.buf)
+	f.pending = append(f.pending, f.buf[:n]...)
+	f.size += int64(n)
+	if err == io.EOF {
+		return n, nil
+	}
+	return n, err
+}
+
+// decode decode the complete records of pending and call fn with them
+func (f *follower[T]) decode(ctx context.Context) error {
+	end := completeRecords(f.pending)
+	if end == 0 {
+		return nil
+	}
+
+	reader := newClientReader(bytes.NewReader(f.pending[:end]), f.readerOption, f.checkpoint)
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		record, meta, err := reader.readRecord()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		if f.option.Header && !f.headerSeen {
+			f.headerSeen = true
+			continue
+		}
+
+		row := new(T)
+		err = unmarshalOneDSlice(record, row, f.readerOption, meta)
+		if err != nil {
+			return err
+		}
+		err = f.fn(row)
+		if err != nil {
+			return err
+		}
+	}
+
+	checkpoint := reader.Checkpoint()
+	f.checkpoint = &checkpoint
+	f.pending = append(f.pending[:0], f.pending[end:]...)
+	return nil
+}
+
+// completeRecords return the length of the complete records at the beginning of data,
+// that is up to the last line terminator out of quotes
+func completeRecords(data []byte) int {
+	end, quoted := 0, false
+	for i, b := range data {
+		switch {
+		case b == '"':
+			quoted = !quoted
+		case b == '\n' && !quoted:
+			end = i + 1
+		}
+	}
+	return end
+}
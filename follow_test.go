@@ -0,0 +1,163 @@
+package easy_csv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testAppendFile(t *testing.T, path string, data string) {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	if _, err = file.WriteString(data); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFollow(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "measurements.csv")
+	testAppendFile(t, path, "ID,Name\n1,a\n")
+
+	rows := make(chan testBatchRow, 16)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- Follow(ctx, path, func(row *testBatchRow) error {
+			rows <- *row
+			return nil
+		}, WithFollowPollInterval(5*time.Millisecond))
+	}()
+
+	expect := func(id int, name string) {
+		t.Helper()
+		select {
+		case row := <-rows:
+			if row.ID != id || row.Name != name {
+				t.Errorf("unexpected row: %+v", row)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatalf("timeout waiting for row %d", id)
+		}
+	}
+	expectNone := func() {
+		t.Helper()
+		select {
+		case row := <-rows:
+			t.Errorf("unexpected row: %+v", row)
+		case <-time.After(50 * time.Millisecond):
+		}
+	}
+
+	expect(1, "a")
+
+	//不完整的行等待换行符
+	testAppendFile(t, path, "2,b")
+	expectNone()
+	testAppendFile(t, path, "\n3,\"c\n")
+	expect(2, "b")
+	expectNone()
+	testAppendFile(t, path, "c\"\n")
+	expect(3, "c\nc")
+
+	//截断后从头读取，跳过新的表头
+	err := os.WriteFile(path, []byte("ID,Name\n4,d\n"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect(4, "d")
+
+	//轮转后读取新文件
+	err = os.Rename(path, path+".1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	testAppendFile(t, path+".1", "5,e\n")
+	testAppendFile(t, path, "ID,Name\n6,f\n")
+	expect(5, "e")
+	expect(6, "f")
+
+	cancel()
+	select {
+	case err = <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("expected context.Canceled, got %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Follow did not stop")
+	}
+}
+
+func TestFollowCallbackError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "measurements.csv")
+	testAppendFile(t, path, "ID,Name\n1,a\n")
+
+	failed := errors.New("stop")
+	err := Follow(context.Background(), path, func(row *testBatchRow) error {
+		return failed
+	}, WithFollowPollInterval(5*time.Millisecond))
+	if !errors.Is(err, failed) {
+		t.Errorf("expected the callback error, got %v", err)
+	}
+}
+
+func TestFollowNonPositiveInterval(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "measurements.csv")
+	testAppendFile(t, path, "ID,Name\n1,a\n")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	err := Follow(ctx, path, func(row *testBatchRow) error {
+		cancel()
+		return nil
+	}, WithFollowPollInterval(0))
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestFollowPendingBounded(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "measurements.csv")
+	data := "ID,Name\n"
+	for i := 1; i <= 5000; i++ {
+		data += fmt.Sprintf("%d,%s\n", i, strings.Repeat("x", 40))
+	}
+	testAppendFile(t, path, data)
+
+	f := &follower[testBatchRow]{
+		path:         path,
+		option:       &FollowOption{Header: true},
+		readerOption: &ClientReaderOption{},
+	}
+	rows, maxPending := 0, 0
+	f.fn = func(row *testBatchRow) error {
+		rows++
+		if len(f.pending) > maxPending {
+			maxPending = len(f.pending)
+		}
+		return nil
+	}
+	err := f.open()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.file.Close()
+
+	err = f.poll(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	//已有数据远大于一块，pending不超过一块加一条不完整的记录
+	if len(data) <= 4*followChunkSize || rows != 5000 {
+		t.Fatalf("unexpected input %d bytes, %d rows", len(data), rows)
+	}
+	if maxPending > followChunkSize+64 || len(f.pending) != 0 {
+		t.Errorf("pending not bounded: max %d, left %d", maxPending, len(f.pending))
+	}
+}
@@ -0,0 +1,70 @@
+package easy_csv
+
+import (
+	"strconv"
+)
+
+// WithWriterFormulaEscape escape the cells which spreadsheet applications would execute as formulas,
+// a cell starting with '=', '+', '-', '@', tab or carriage return is prefixed with a single quote.
+//
+// numericColumns: names of columns whose numeric values like '-12.5' are written as is
+func WithWriterFormulaEscape(numericColumns ...string) ClientWriterOptionFunc {
+	return func(opt *ClientWriterOption) {
+		opt.EscapeFormula = true
+		opt.FormulaNumericColumns = numericColumns
+	}
+}
+
+// escapeFormulas return the records with formula cells escaped,data is not modified.
+// header is used to find the numeric columns.
+func escapeFormulas(data [][]string, header []string, numericColumns []string) [][]string {
+	numeric := make(map[int]bool)
+	for i, name := range header {
+		for _, column := range numericColumns {
+			if name == column {
+				numeric[i] = true
+			}
+		}
+	}
+
+	escaped := make([][]string, len(data))
+	for i, row := range data {
+		var escapedRow []string
+		for j, cell := range row {
+			if !isFormula(cell) {
+				continue
+			}
+			if numeric[j] && isNumber(cell) {
+				continue
+			}
+
+			//只在需要时复制，避免修改调用方的数据
+			if escapedRow == nil {
+				escapedRow = append([]string(nil), row...)
+			}
+			escapedRow[j] = "'" + cell
+		}
+
+		if escapedRow == nil {
+			escapedRow = row
+		}
+		escaped[i] = escapedRow
+	}
+	return escaped
+}
+
+func isFormula(cell string) bool {
+	if len(cell) == 0 {
+		return false
+	}
+	switch cell[0] {
+	case '=', '+', '-', '@', '\t', '\r':
+		return true
+	}
+	return false
+}
+
+func isNumber(cell string) bool {
+	_, err := strconv.ParseFloat(cell, 64)
+	return err == nil
+}
@@ -0,0 +1,53 @@
+package easy_csv
+
+import (
+	"strings"
+	"testing"
+)
+
+type testFormulaBean struct {
+	Name    string  `csv:"name"`
+	Balance float64 `csv:"balance"`
+	Note    string  `csv:"note"`
+}
+
+func TestClientWriter_FormulaEscape(t *testing.T) {
+	buf := &strings.Builder{}
+	writer := NewClientWriter(buf, WithWriterFormulaEscape("balance"))
+
+	list := []testFormulaBean{
+		{Name: "=HYPERLINK(\"http://x\")", Balance: -12.5, Note: "-12.5"},
+		{Name: "@SUM(A1)", Balance: 3, Note: "+1"},
+	}
+	err := writer.WriteRows2File(list, true)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	err = writer.WriteRow2File(&testFormulaBean{Name: "bob", Balance: -1, Note: "\tx"})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	expect := "name,balance,note\n" +
+		"\"'=HYPERLINK(\"\"http://x\"\")\",-12.5,'-12.5\n" +
+		"'@SUM(A1),3,'+1\n" +
+		"bob,-1,'\tx\n"
+	if buf.String() != expect {
+		t.Errorf("unexpected data:\n%s", buf.String())
+	}
+
+	//原始数据以第一行为表头
+	buf.Reset()
+	writer = NewClientWriter(buf, WithWriterFormulaEscape("amount"))
+	data := [][]string{{"amount", "memo"}, {"-3", "-3"}}
+	err = writer.WriteString2File(data)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if buf.String() != "amount,memo\n-3,'-3\n" || data[1][1] != "-3" {
+		t.Errorf("unexpected data:\n%s", buf.String())
+	}
+}
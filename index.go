@@ -0,0 +1,123 @@
+package easy_csv
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// RowIndex records the position of every Nth record of an input for random access.
+// Rows are numbered from 0 in the order they are read,the header is row 0.
+type RowIndex struct {
+	Every   int             `json:"every"`   // interval of the indexed rows
+	Rows    int             `json:"rows"`    // records of the input
+	Size    int64           `json:"size"`    // bytes of the input
+	Header  []string        `json:"header"`  // the first record
+	Comma   rune            `json:"comma"`   // field delimiter of the input
+	Entries []RowIndexEntry `json:"entries"` // Entries[i] is the position of row i*Every,the last one may be the end of input
+}
+
+// RowIndexEntry is the position of an indexed row
+type RowIndexEntry struct {
+	Offset int64 `json:"offset"` // byte offset of the row
+	Line   int   `json:"line"`   // lines of input before the row
+}
+
+// BuildRowIndex read input to the end and record the position of every every-th row.
+// The options should be the same as the readers seeking with the index.
+func BuildRowIndex(input io.Reader, every int, opts ...ClientReaderOptionFunc) (*RowIndex, error) {
+	if every <= 0 {
+		return nil, errors.New("index interval must be positive")
+	}
+
+	reader := NewClientReader(input, opts...)
+	index := &RowIndex{Every: every, Entries: make([]RowIndexEntry, 0)}
+	for {
+		if index.Rows%every == 0 {
+			index.Entries = append(index.Entries, RowIndexEntry{
+				Offset: reader.offsetBase + reader.r.InputOffset(),
+				Line:   reader.lines,
+			})
+		}
+
+		_, _, err := reader.readRecord()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		index.Rows++
+	}
+
+	index.Size = reader.offsetBase + reader.r.InputOffset()
+	index.Header = reader.Header()
+	index.Comma = reader.r.Comma
+	return index, nil
+}
+
+// LoadRowIndex read an index saved by Save
+func LoadRowIndex(path string) (*RowIndex, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	index := &RowIndex{}
+	err = json.Unmarshal(data, index)
+	if err != nil {
+		return nil, err
+	}
+	if index.Every <= 0 {
+		return nil, fmt.Errorf("invalid index %s", path)
+	}
+	return index, nil
+}
+
+// Save write the index to a sidecar file as JSON
+func (index *RowIndex) Save(path string) error {
+	data, err := json.Marshal(index)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// Seek create a reader of input whose next record is row.
+// The reader seeks to the nearest indexed row before it,and reads at most Every-1 rows to reach it.
+// A reader at the end of input is returned if row equals Rows.
+func (index *RowIndex) Seek(input io.ReadSeeker, row int, opts ...ClientReaderOptionFunc) (*ClientReader, error) {
+	if row < 0 || row > index.Rows {
+		return nil, fmt.Errorf("row %d out of range [0, %d]", row, index.Rows)
+	}
+
+	i := row / index.Every
+	if i >= len(index.Entries) {
+		return nil, errors.New("index has no entry for the row")
+	}
+	checkpoint := Checkpoint{
+		Offset: index.Entries[i].Offset,
+		Line:   index.Entries[i].Line,
+		Rows:   i * index.Every,
+		Header: index.Header,
+		Comma:  index.Comma,
+	}
+	//第0行之前没有表头
+	if checkpoint.Rows == 0 {
+		checkpoint.Header = nil
+	}
+
+	reader, err := ResumeClientReader(input, checkpoint, opts...)
+	if err != nil {
+		return nil, err
+	}
+	for reader.rows < row {
+		_, _, err = reader.readRecord()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return reader, nil
+}
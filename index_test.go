@@ -0,0 +1,99 @@
+package easy_csv
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRowIndex(t *testing.T) {
+	data := "ID,Name\n"
+	for i := 1; i <= 10; i++ {
+		//偶数行的字段含换行
+		if i%2 == 0 {
+			data += fmt.Sprintf("%d,\"n\n%d\"\n", i, i)
+		} else {
+			data += fmt.Sprintf("%d,n%d\n", i, i)
+		}
+	}
+
+	index, err := BuildRowIndex(strings.NewReader(data), 4)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if index.Rows != 11 || len(index.Entries) != 3 || index.Size != int64(len(data)) {
+		t.Errorf("unexpected index: %+v", index)
+		return
+	}
+
+	path := filepath.Join(t.TempDir(), "data.csv.idx")
+	err = index.Save(path)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	index, err = LoadRowIndex(path)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	for _, k := range []int{1, 4, 6, 9} {
+		reader, err := index.Seek(strings.NewReader(data), k)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		row := testBatchRow{}
+		err = reader.ReadRowFromFile(&row)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if row.ID != k {
+			t.Errorf("row %d: unexpected row %+v", k, row)
+		}
+		if checkpoint := reader.Checkpoint(); checkpoint.Line != k+k/2+1 {
+			t.Errorf("row %d: unexpected line %d", k, checkpoint.Line)
+		}
+	}
+
+	reader, err := index.Seek(strings.NewReader(data), 11)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if _, err = reader.Read(); err == nil {
+		t.Error("expected EOF after the last row")
+	}
+	if _, err = index.Seek(strings.NewReader(data), 12); err == nil {
+		t.Error("expected an out of range error")
+	}
+}
+
+func TestRowIndexExcel(t *testing.T) {
+	data := "\ufeffsep=;\r\nID;Name\r\n1;a\r\n2;b\r\n"
+	index, err := BuildRowIndex(strings.NewReader(data), 1, WithReaderExcel())
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	//表头在第2行，之前有sep=行
+	if index.Entries[0].Line != 1 || index.Entries[1].Line != 2 {
+		t.Errorf("unexpected entries: %+v", index.Entries)
+		return
+	}
+
+	reader, err := index.Seek(strings.NewReader(data), 2, WithReaderExcel())
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	row := testBatchRow{}
+	err = reader.ReadRowFromFile(&row)
+	if err != nil || row.ID != 2 || row.Name != "b" {
+		t.Errorf("unexpected row %+v: %v", row, err)
+	}
+}
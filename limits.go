@@ -0,0 +1,219 @@
+package easy_csv
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"unicode/utf8"
+)
+
+// ErrLimitExceeded is wrapped by the *LimitError returned when input exceeds a limit of ClientReaderOption
+var ErrLimitExceeded = errors.New("easy_csv: limit exceeded")
+
+// LimitError reports the limit exceeded by the input and where
+type LimitError struct {
+	Limit  string // name of the option,like "MaxFieldSize"
+	Max    int64  // value of the option
+	Line   int    // line where the limit was exceeded,starting at 1
+	Column int    // field where the limit was exceeded,starting at 1,0 if the limit is not of a field
+	Offset int64  // byte offset where the limit was exceeded
+}
+
+func (e *LimitError) Error() string {
+	if e.Column > 0 {
+		return fmt.Sprintf("line %d, column %d: %s %d exceeded", e.Line, e.Column, e.Limit, e.Max)
+	}
+	return fmt.Sprintf("line %d: %s %d exceeded", e.Line, e.Limit, e.Max)
+}
+
+func (e *LimitError) Unwrap() error {
+	return ErrLimitExceeded
+}
+
+// WithReaderMaxFieldSize limit the bytes of a field
+func WithReaderMaxFieldSize(size int) ClientReaderOptionFunc {
+	return func(opt *ClientReaderOption) {
+		opt.MaxFieldSize = size
+	}
+}
+
+// WithReaderMaxFields limit the fields of a record
+func WithReaderMaxFields(fields int) ClientReaderOptionFunc {
+	return func(opt *ClientReaderOption) {
+		opt.MaxFields = fields
+	}
+}
+
+// WithReaderMaxRows limit the records read,the header and the rows rejected by RaggedReject are counted
+func WithReaderMaxRows(rows int) ClientReaderOptionFunc {
+	return func(opt *ClientReaderOption) {
+		opt.MaxRows = rows
+	}
+}
+
+// WithReaderMaxBytes limit the bytes of the input
+func WithReaderMaxBytes(n int64) ClientReaderOptionFunc {
+	return func(opt *ClientReaderOption) {
+		opt.MaxBytes = n
+	}
+}
+
+func hasLimits(option *ClientReaderOption) bool {
+	return option.MaxFieldSize > 0 || option.MaxFields > 0 || option.MaxBytes > 0
+}
+
+// the states of limitReader scanning a field
+const (
+	scanFieldStart = iota
+	scanUnquoted
+	scanQuoted
+	scanQuoteInQuoted // a quote in a quoted field,either closing it or escaping the next quote
+	scanComment
+)
+
+// limitReader checks the limits on the raw input before encoding/csv buffers a whole record,
+// so that a huge field or record is rejected while its bytes are read.
+// The fields are scanned only if Comma and Comment are ASCII,
+// the records are checked again after they are read anyway.
+type limitReader struct {
+	r       io.Reader
+	option  *ClientReaderOption
+	comma   byte
+	comment byte
+	scan    bool
+
+	n      int64 // bytes read
+	line   int
+	state  int
+	field  int64 // bytes of the current field after unquoting
+	fields int   // fields of the current record
+	err    error
+}
+
+// newLimitReader create a limitReader of the input after the Excel preamble,comma is the delimiter of the input
+func newLimitReader(r io.Reader, option *ClientReaderOption, comma rune) *limitReader {
+	l := &limitReader{
+		r:      r,
+		option: option,
+		line:   1,
+		fields: 1,
+		scan:   comma < utf8.RuneSelf && option.Comment < utf8.RuneSelf,
+	}
+	l.comma = byte(comma)
+	l.comment = byte(option.Comment)
+	return l
+}
+
+func (l *limitReader) Read(p []byte) (int, error) {
+	if l.err != nil {
+		return 0, l.err
+	}
+
+	//多读一个字节以判断输入是否超过MaxBytes
+	if max := l.option.MaxBytes; max > 0 && int64(len(p)) > max-l.n+1 {
+		p = p[:max-l.n+1]
+	}
+
+	n, err := l.r.Read(p)
+	for i := 0; i < n; i++ {
+		if l.option.MaxBytes > 0 && l.n+int64(i) >= l.option.MaxBytes {
+			l.err = &LimitError{Limit: "MaxBytes", Max: l.option.MaxBytes, Line: l.line, Offset: l.n + int64(i)}
+		} else if l.scan {
+			l.err = l.scanByte(p[i], l.n+int64(i))
+		}
+		if l.err != nil {
+			l.n += int64(i)
+			return i, l.err
+		}
+	}
+	l.n += int64(n)
+	return n, err
+}
+
+// scanByte follow the state of field with b and check the field size and record width
+func (l *limitReader) scanByte(b byte, offset int64) error {
+	if b == '\r' {
+		return nil
+	}
+
+	switch l.state {
+	case scanComment:
+		if b == '\n' {
+			l.line++
+			l.state = scanFieldStart
+		}
+		return nil
+	case scanQuoted:
+		if b == '"' {
+			l.state = scanQuoteInQuoted
+			return nil
+		}
+		if b == '\n' {
+			l.line++
+		}
+		return l.grow(offset)
+	case scanQuoteInQuoted:
+		if b == '"' {
+			l.state = scanQuoted
+			return l.grow(offset)
+		}
+	}
+
+	switch {
+	case b == '\n':
+		l.line++
+		l.state = scanFieldStart
+		l.field = 0
+		l.fields = 1
+		return nil
+	case b == l.comma:
+		l.state = scanFieldStart
+		l.field = 0
+		l.fields++
+		if max := l.option.MaxFields; max > 0 && l.fields > max {
+			return &LimitError{Limit: "MaxFields", Max: int64(max), Line: l.line, Column: l.fields, Offset: offset}
+		}
+		return nil
+	case l.state == scanFieldStart && l.fields == 1 && l.comment != 0 && b == l.comment:
+		l.state = scanComment
+		return nil
+	case l.state == scanFieldStart && b == '"':
+		l.state = scanQuoted
+		return nil
+	case l.state == scanFieldStart && l.option.TrimLeadingSpace && (b == ' ' || b == '\t'):
+		return nil
+	}
+
+	l.state = scanUnquoted
+	return l.grow(offset)
+}
+
+func (l *limitReader) grow(offset int64) error {
+	l.field++
+	if max := l.option.MaxFieldSize; max > 0 && l.field > int64(max) {
+		return &LimitError{Limit: "MaxFieldSize", Max: int64(max), Line: l.line, Column: l.fields, Offset: offset}
+	}
+	return nil
+}
+
+// checkLimits check a record read by encoding/csv before the RaggedPolicy,so that rejected rows are counted too.
+// It is needed for the records not scanned by limitReader and for MaxRows.
+func (reader *ClientReader) checkLimits(meta *rowMeta) error {
+	option := reader.option
+	reader.scanned++
+	if option.MaxRows > 0 && reader.scanned > option.MaxRows {
+		return &LimitError{Limit: "MaxRows", Max: int64(option.MaxRows), Line: meta.line, Offset: meta.offset}
+	}
+	if option.MaxFields > 0 && len(meta.raw) > option.MaxFields {
+		return &LimitError{Limit: "MaxFields", Max: int64(option.MaxFields), Line: meta.line, Column: option.MaxFields + 1, Offset: meta.offset}
+	}
+	if option.MaxFieldSize > 0 {
+		for i, field := range meta.raw {
+			if len(field) > option.MaxFieldSize {
+				line, _ := reader.r.FieldPos(i)
+				return &LimitError{Limit: "MaxFieldSize", Max: int64(option.MaxFieldSize), Line: reader.lineBase + line, Column: i + 1, Offset: meta.offset}
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+package easy_csv
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+// testInfiniteReader is an endless quoted field
+type testInfiniteReader struct {
+	n int64
+}
+
+func (r *testInfiniteReader) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = 'x'
+	}
+	if r.n == 0 {
+		copy(p, `a,"`)
+	}
+	r.n += int64(len(p))
+	return len(p), nil
+}
+
+func TestReaderMaxFieldSize(t *testing.T) {
+	input := &testInfiniteReader{}
+	reader := NewClientReader(input, WithReaderMaxFieldSize(100))
+
+	_, err := reader.Read()
+	var limitErr *LimitError
+	if !errors.As(err, &limitErr) || !errors.Is(err, ErrLimitExceeded) {
+		t.Errorf("unexpected error: %v", err)
+		return
+	}
+	if limitErr.Limit != "MaxFieldSize" || limitErr.Line != 1 || limitErr.Column != 2 || limitErr.Offset != 103 {
+		t.Errorf("unexpected limit error: %+v", limitErr)
+	}
+	//超限时只读取了少量输入
+	if input.n > 64<<10 {
+		t.Errorf("too much input read: %d", input.n)
+	}
+
+	//转义的引号按一个字节计算
+	reader = NewClientReader(strings.NewReader("\"a\"\"b\",c\n"), WithReaderMaxFieldSize(3))
+	record, err := reader.Read()
+	if err != nil || record[0] != `a"b` {
+		t.Errorf("unexpected record %q: %v", record, err)
+	}
+}
+
+func TestReaderMaxFields(t *testing.T) {
+	reader := NewClientReader(strings.NewReader("a,b\n\"c,d\",e,f\n"), WithReaderMaxFields(2))
+
+	_, err := reader.Read()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	_, err = reader.Read()
+	var limitErr *LimitError
+	if !errors.As(err, &limitErr) || limitErr.Limit != "MaxFields" || limitErr.Line != 2 || limitErr.Column != 3 {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestReaderMaxRowsAndBytes(t *testing.T) {
+	reader := NewClientReader(strings.NewReader("1,a\n2,b\n3,c\n"), WithReaderMaxRows(2))
+	_, err := reader.ReadAll()
+	var limitErr *LimitError
+	if !errors.As(err, &limitErr) || limitErr.Limit != "MaxRows" || limitErr.Line != 3 {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	reader = NewClientReader(strings.NewReader("1,a\n2,b\n3,c\n"), WithReaderMaxBytes(10))
+	for i := 0; i < 2; i++ {
+		if _, err = reader.Read(); err != nil {
+			t.Error(err)
+			return
+		}
+	}
+	_, err = reader.Read()
+	if !errors.As(err, &limitErr) || limitErr.Limit != "MaxBytes" || limitErr.Offset != 10 {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	//恰好等于限制时正常读完
+	reader = NewClientReader(strings.NewReader("1,a\n2,b\n3,c\n"), WithReaderMaxBytes(12))
+	records, err := reader.ReadAll()
+	if err != nil || len(records) != 3 {
+		t.Errorf("unexpected records %v: %v", records, err)
+	}
+	if _, err = reader.Read(); err != io.EOF {
+		t.Errorf("expected EOF, got %v", err)
+	}
+}
+
+func TestReaderLimitsExcelSep(t *testing.T) {
+	data := "\ufeffsep=;\r\nid;note\r\n1;a,b,c,d,e\r\n"
+	reader := NewClientReader(strings.NewReader(data), WithReaderExcel(), WithReaderMaxFields(3), WithReaderMaxBytes(int64(len(data))))
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if len(records) != 2 || records[1][1] != "a,b,c,d,e" {
+		t.Errorf("unexpected records: %q", records)
+	}
+
+	//分号分隔的字段数仍受限制，行号包含sep=行
+	reader = NewClientReader(strings.NewReader("\ufeffsep=;\r\nid;note\r\n1;a;b;c\r\n"), WithReaderExcel(), WithReaderMaxFields(3))
+	_, err = reader.ReadAll()
+	var limitErr *LimitError
+	if !errors.As(err, &limitErr) || limitErr.Line != 3 || limitErr.Column != 4 {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestReaderMaxRowsRejected(t *testing.T) {
+	data := "ID,Name\n" + strings.Repeat("1\n", 1000)
+	collector := NewErrorCollector()
+	reader := NewClientReader(strings.NewReader(data), WithReaderMaxRows(10),
+		WithReaderRaggedPolicy(RaggedReject), WithReaderErrorCollector(collector))
+
+	_, err := reader.ReadAll()
+	var limitErr *LimitError
+	if !errors.As(err, &limitErr) || limitErr.Limit != "MaxRows" || limitErr.Line != 11 {
+		t.Errorf("unexpected error: %v", err)
+	}
+	//被拒绝的行计入MaxRows，收集的错误数量有上限
+	if collector.Len() != 9 {
+		t.Errorf("unexpected collected errors: %d", collector.Len())
+	}
+}
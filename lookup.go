@@ -0,0 +1,270 @@
+package easy_csv
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+var (
+	// ErrKeyNotFound is returned by Lookup when no record has the key
+	ErrKeyNotFound = errors.New("easy_csv: key not found")
+	// ErrIndexStale is returned when the file has changed since the index was built
+	ErrIndexStale = errors.New("easy_csv: file changed since the index was built")
+)
+
+// KeyIndex maps the values of key columns to the records of a csv file,
+// so that a record is read by its key without loading the whole file.
+// The first record of the file is the header,the key columns are found by name in it.
+// A KeyIndex is safe for concurrent use.
+type KeyIndex[T any] struct {
+	path   string
+	option *ClientReaderOption
+	data   keyIndexData
+
+	indexed os.FileInfo // the file when the index is built or loaded
+
+	mu   sync.Mutex
+	file *os.File
+}
+
+// keyIndexData is the persisted part of KeyIndex
+type keyIndexData struct {
+	Columns []string                 `json:"columns"`
+	Header  []string                 `json:"header"`
+	Comma   rune                     `json:"comma"`
+	Size    int64                    `json:"size"`     // size of the file when indexed
+	ModTime int64                    `json:"mod_time"` // modification time of the file when indexed,in nanoseconds
+	Entries map[string]RowIndexEntry `json:"entries"`
+}
+
+// BuildKeyIndex read the csv file at path and index its records by the columns.
+// A key appearing in more than one record is an error.
+// The options are used to read the file for the index and for Lookup.
+func BuildKeyIndex[T any](path string, columns []string, opts ...ClientReaderOptionFunc) (*KeyIndex[T], error) {
+	if len(columns) == 0 {
+		return nil, errors.New("key columns can not be empty")
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	index := newKeyIndex[T](path, opts)
+	reader := newClientReader(file, index.option, nil)
+	header, _, err := reader.readRecord()
+	if err != nil {
+		return nil, fmt.Errorf("read header: %w", err)
+	}
+
+	positions := make([]int, len(columns))
+	for i, column := range columns {
+		positions[i] = -1
+		for j, name := range header {
+			if name == column {
+				positions[i] = j
+				break
+			}
+		}
+		if positions[i] < 0 {
+			return nil, fmt.Errorf("key column %s not found in header", column)
+		}
+	}
+
+	index.data = keyIndexData{
+		Columns: columns,
+		Header:  reader.Header(),
+		Comma:   reader.r.Comma,
+		Size:    info.Size(),
+		ModTime: info.ModTime().UnixNano(),
+		Entries: make(map[string]RowIndexEntry),
+	}
+	index.indexed = info
+	for {
+		entry := RowIndexEntry{Offset: reader.offsetBase + reader.r.InputOffset(), Line: reader.lines}
+		record, meta, err := reader.readRecord()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		values := make([]string, len(positions))
+		for i, position := range positions {
+			if position < len(record) {
+				values[i] = record[position]
+			}
+		}
+		key := joinKey(values)
+		if _, ok := index.data.Entries[key]; ok {
+			return nil, fmt.Errorf("line %d: duplicate key %q", meta.line, strings.Join(values, ","))
+		}
+		index.data.Entries[key] = entry
+	}
+	return index, nil
+}
+
+// LoadKeyIndex read an index saved by Save for the csv file at path,
+// an error wrapping ErrIndexStale is returned if the file has changed since the index was built
+func LoadKeyIndex[T any](indexPath string, path string, opts ...ClientReaderOptionFunc) (*KeyIndex[T], error) {
+	data, err := os.ReadFile(indexPath)
+	if err != nil {
+		return nil, err
+	}
+
+	index := newKeyIndex[T](path, opts)
+	err = json.Unmarshal(data, &index.data)
+	if err != nil {
+		return nil, err
+	}
+	if len(index.data.Columns) == 0 || index.data.Entries == nil {
+		return nil, fmt.Errorf("invalid index %s", indexPath)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	err = index.check(info)
+	if err != nil {
+		return nil, err
+	}
+	index.indexed = info
+	return index, nil
+}
+
+func newKeyIndex[T any](path string, opts []ClientReaderOptionFunc) *KeyIndex[T] {
+	option := &ClientReaderOption{}
+	for _, o := range opts {
+		o(option)
+	}
+	return &KeyIndex[T]{path: path, option: option}
+}
+
+// Save write the index to a sidecar file as JSON
+func (index *KeyIndex[T]) Save(indexPath string) error {
+	data, err := json.Marshal(index.data)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(indexPath, data, 0644)
+}
+
+// Len return the number of keys
+func (index *KeyIndex[T]) Len() int {
+	return len(index.data.Entries)
+}
+
+// Lookup read the record whose key columns have the values of key,in the order of the columns.
+// An error wrapping ErrKeyNotFound is returned if no record has the key,
+// and an error wrapping ErrIndexStale if the file has changed since the index was built.
+func (index *KeyIndex[T]) Lookup(key ...string) (*T, error) {
+	if len(key) != len(index.data.Columns) {
+		return nil, fmt.Errorf("key needs %d values,got %d", len(index.data.Columns), len(key))
+	}
+
+	index.mu.Lock()
+	defer index.mu.Unlock()
+
+	//先检查文件是否变化，新增的键应报告索引失效而不是找不到
+	err := index.open()
+	if err != nil {
+		return nil, err
+	}
+
+	entry, ok := index.data.Entries[joinKey(key)]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrKeyNotFound, strings.Join(key, ","))
+	}
+
+	_, err = index.file.Seek(entry.Offset, io.SeekStart)
+	if err != nil {
+		return nil, err
+	}
+	reader := newClientReader(index.file, index.option, &Checkpoint{
+		Offset: entry.Offset,
+		Line:   entry.Line,
+		Rows:   1,
+		Header: index.data.Header,
+		Comma:  index.data.Comma,
+	})
+
+	row := new(T)
+	err = reader.ReadRowFromFile(row)
+	if err != nil {
+		return nil, err
+	}
+	return row, nil
+}
+
+// Close close the file opened by Lookup
+func (index *KeyIndex[T]) Close() error {
+	index.mu.Lock()
+	defer index.mu.Unlock()
+
+	if index.file == nil {
+		return nil
+	}
+	err := index.file.Close()
+	index.file = nil
+	return err
+}
+
+// open return the file at path,
+// an error wrapping ErrIndexStale is returned if another file has been renamed to path
+func (index *KeyIndex[T]) open() error {
+	info, err := os.Stat(index.path)
+	if err != nil {
+		return err
+	}
+	err = index.check(info)
+	if err != nil {
+		return err
+	}
+	if index.file != nil {
+		return nil
+	}
+
+	file, err := os.Open(index.path)
+	if err != nil {
+		return err
+	}
+	info, err = file.Stat()
+	if err == nil {
+		err = index.check(info)
+	}
+	if err != nil {
+		file.Close()
+		return err
+	}
+	index.file = file
+	return nil
+}
+
+// check report whether the file of info is the indexed file and has not changed,
+// the size and modification time are persisted,the identity of the file is checked in the process only
+func (index *KeyIndex[T]) check(info os.FileInfo) error {
+	if index.indexed != nil && !os.SameFile(index.indexed, info) {
+		return fmt.Errorf("%w: %s", ErrIndexStale, index.path)
+	}
+	if info.Size() != index.data.Size || info.ModTime().UnixNano() != index.data.ModTime {
+		return fmt.Errorf("%w: %s", ErrIndexStale, index.path)
+	}
+	return nil
+}
+
+// joinKey join the values of key columns with a NUL byte
+func joinKey(values []string) string {
+	return strings.Join(values, "\x00")
+}
@@ -0,0 +1,157 @@
+package easy_csv
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type testLookupRow struct {
+	Country string
+	City    string
+	Code    int
+}
+
+func TestKeyIndex(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "cities.csv")
+	err := os.WriteFile(path, []byte("Country,City,Code\nCN,北京,10\nCN,\"上海\n浦东\",21\nUS,NYC,212\n"), 0644)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	index, err := BuildKeyIndex[testLookupRow](path, []string{"Country", "Code"})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	indexPath := path + ".keys"
+	err = index.Save(indexPath)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	index, err = LoadKeyIndex[testLookupRow](indexPath, path)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer index.Close()
+	if index.Len() != 3 {
+		t.Errorf("unexpected keys: %d", index.Len())
+	}
+
+	row, err := index.Lookup("CN", "21")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if row.City != "上海\n浦东" || row.Code != 21 {
+		t.Errorf("unexpected row: %+v", row)
+	}
+	row, err = index.Lookup("US", "212")
+	if err != nil || row.City != "NYC" {
+		t.Errorf("unexpected row %+v: %v", row, err)
+	}
+
+	if _, err = index.Lookup("US", "1"); !errors.Is(err, ErrKeyNotFound) {
+		t.Errorf("expected ErrKeyNotFound, got %v", err)
+	}
+
+	//文件修改后索引失效
+	err = os.WriteFile(path, []byte("Country,City,Code\nCN,北京,10\n"), 0644)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	_ = os.Chtimes(path, time.Now(), time.Now().Add(time.Second))
+	if _, err = index.Lookup("CN", "10"); !errors.Is(err, ErrIndexStale) {
+		t.Errorf("expected ErrIndexStale, got %v", err)
+	}
+	if _, err = LoadKeyIndex[testLookupRow](indexPath, path); !errors.Is(err, ErrIndexStale) {
+		t.Errorf("expected ErrIndexStale, got %v", err)
+	}
+}
+
+func TestBuildKeyIndexDuplicate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dup.csv")
+	err := os.WriteFile(path, []byte("Country,City,Code\nCN,北京,10\nCN,天津,22\n"), 0644)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if _, err = BuildKeyIndex[testLookupRow](path, []string{"Country"}); err == nil {
+		t.Error("expected a duplicate key error")
+	}
+	if _, err = BuildKeyIndex[testLookupRow](path, []string{"Zip"}); err == nil {
+		t.Error("expected a missing column error")
+	}
+}
+
+func TestKeyIndexReplacedByRename(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cities.csv")
+	err := os.WriteFile(path, []byte("Country,City,Code\nCN,BJ,10\n"), 0644)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	index, err := BuildKeyIndex[testLookupRow](path, []string{"Country"})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer index.Close()
+	if _, err = index.Lookup("CN"); err != nil {
+		t.Error(err)
+		return
+	}
+
+	//通过重命名替换文件，已打开的文件句柄仍指向旧文件
+	writer, err := NewAtomicFileWriter(path)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	err = writer.WriteString2File([][]string{{"Country", "City", "Code"}, {"CN", "SH", "21"}})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	err = writer.Close()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if row, err := index.Lookup("CN"); !errors.Is(err, ErrIndexStale) {
+		t.Errorf("expected ErrIndexStale, got %+v %v", row, err)
+	}
+}
+
+func TestKeyIndexAppendedKey(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cities.csv")
+	err := os.WriteFile(path, []byte("Country,City,Code\nCN,BJ,10\n"), 0644)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	index, err := BuildKeyIndex[testLookupRow](path, []string{"Country"})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer index.Close()
+
+	testAppendFile(t, path, "US,NYC,212\n")
+	//新增的键不在索引中，应报告索引失效
+	if _, err = index.Lookup("US"); !errors.Is(err, ErrIndexStale) {
+		t.Errorf("expected ErrIndexStale, got %v", err)
+	}
+}
@@ -0,0 +1,297 @@
+package easy_csv
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// MaskFunc mask a sensitive value.
+//
+// value string: the value of cell
+//
+// maskChar rune: the rune used to hide characters,it is '*' unless the tag has option `char=`
+type MaskFunc func(value string, maskChar rune) string
+
+var (
+	maskersMu sync.RWMutex
+	maskers   = map[string]MaskFunc{
+		"phone":    maskPhone,
+		"email":    maskEmail,
+		"idcard":   maskIDCard,
+		"bankcard": maskBankCard,
+		"name":     maskName,
+		"address":  maskAddress,
+		"ip":       maskIP,
+	}
+)
+
+const (
+	maskSuffix      = "_desensitization"
+	defaultMaskChar = '*'
+)
+
+// RegisterMasker register a masker by name.
+// A masker can be used in csv tag as option `<name>_desensitization` or `mask=<name>`,
+// for example `csv:"手机号,phone_desensitization"` or `csv:"卡号,mask=bankcard,char=#"`.
+// A registered masker replaces the masker with the same name.
+func RegisterMasker(name string, fn MaskFunc) {
+	maskersMu.Lock()
+	defer maskersMu.Unlock()
+	maskers[name] = fn
+}
+
+func lookupMasker(name string) (MaskFunc, bool) {
+	maskersMu.RLock()
+	defer maskersMu.RUnlock()
+	fn, ok := maskers[name]
+	return fn, ok
+}
+
+// MaskPolicy overrides the maskers declared in struct tags when writing,
+// so that the same structure can be exported masked or unmasked without another type.
+type MaskPolicy struct {
+	// DisableTags ignore all maskers declared in struct tags
+	DisableTags bool
+
+	// Columns masker specs by column name or field name,like "phone", "mask=phone" or "keep(3,4)".
+	// The spec of a column replaces the maskers of its tag,and an empty spec or "none" leaves the column unmasked.
+	Columns map[string]string
+
+	err error // every write fails with it,set for an unknown role of MaskProfiles
+}
+
+// ErrUnknownMaskRole is returned when MaskProfiles has no policy of a role
+var ErrUnknownMaskRole = errors.New("easy_csv: unknown mask profile role")
+
+// MaskProfiles are mask policies by role,for example an unmasked policy for "admin" and a strict one for "vendor"
+type MaskProfiles map[string]*MaskPolicy
+
+// WithWriterMaskPolicy set the mask policy overriding the maskers of struct tags
+func WithWriterMaskPolicy(policy *MaskPolicy) ClientWriterOptionFunc {
+	return func(opt *ClientWriterOption) {
+		opt.MaskPolicy = policy
+	}
+}
+
+// Policy return the mask policy of role,an error wrapping ErrUnknownMaskRole if profiles has no such role
+func (profiles MaskProfiles) Policy(role string) (*MaskPolicy, error) {
+	policy, ok := profiles[role]
+	if !ok || policy == nil {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownMaskRole, role)
+	}
+	return policy, nil
+}
+
+// WithWriterMaskProfile set the mask policy of role.
+// If profiles has no such role every write of structures fails with an error wrapping ErrUnknownMaskRole,
+// so that a misspelled role never exports the columns unmasked. Use MaskProfiles.Policy to check a role in advance.
+func WithWriterMaskProfile(profiles MaskProfiles, role string) ClientWriterOptionFunc {
+	return func(opt *ClientWriterOption) {
+		policy, err := profiles.Policy(role)
+		if err != nil {
+			policy = &MaskPolicy{err: err}
+		}
+		opt.MaskPolicy = policy
+	}
+}
+
+func (option *ClientWriterOption) maskPolicy() *MaskPolicy {
+	if option == nil {
+		return nil
+	}
+	return option.MaskPolicy
+}
+
+func (policy *MaskPolicy) tagsDisabled() bool {
+	return policy != nil && policy.DisableTags
+}
+
+// masker return the masker of a column given by the policy.
+// overridden is false if the policy leaves the column to its tag,fn is nil if the column is unmasked.
+func (policy *MaskPolicy) masker(column, field string) (fn MaskFunc, overridden bool, err error) {
+	if policy == nil {
+		return nil, false, nil
+	}
+	if policy.err != nil {
+		return nil, false, policy.err
+	}
+
+	spec, ok := policy.Columns[column]
+	if !ok {
+		spec, ok = policy.Columns[field]
+	}
+	if !ok {
+		return nil, false, nil
+	}
+
+	spec = strings.TrimPrefix(spec, "mask=")
+	if len(spec) == 0 || spec == "none" {
+		return nil, true, nil
+	}
+
+	fn, err = parseMaskSpec(spec)
+	if err != nil {
+		return nil, false, fmt.Errorf("mask policy of column %s: %w", column, err)
+	}
+	return fn, true, nil
+}
+
+// maskerOf return the masker of a tag option.
+// ok is false if the option is not a masking option,err is not nil if the option names an unknown masker.
+func maskerOf(opt tagOption) (fn MaskFunc, ok bool, err error) {
+	var spec string
+	switch {
+	case opt.key == "mask":
+		spec = opt.value
+	case strings.HasSuffix(opt.key, maskSuffix):
+		spec = strings.TrimSuffix(opt.key, maskSuffix)
+	default:
+		return nil, false, nil
+	}
+
+	fn, err = parseMaskSpec(spec)
+	if err != nil {
+		return nil, false, err
+	}
+	return fn, true, nil
+}
+
+// parseMaskSpec parse a masker name or a rule like 'keep(3,4)'
+func parseMaskSpec(spec string) (MaskFunc, error) {
+	if strings.HasPrefix(spec, "keep(") && strings.HasSuffix(spec, ")") {
+		args := strings.Split(strings.TrimSuffix(strings.TrimPrefix(spec, "keep("), ")"), ",")
+		if len(args) != 2 {
+			return nil, errors.New(fmt.Sprintf("invalid mask rule %s", spec))
+		}
+		head, err := strconv.Atoi(strings.TrimSpace(args[0]))
+		if err != nil || head < 0 {
+			return nil, errors.New(fmt.Sprintf("invalid mask rule %s", spec))
+		}
+		tail, err := strconv.Atoi(strings.TrimSpace(args[1]))
+		if err != nil || tail < 0 {
+			return nil, errors.New(fmt.Sprintf("invalid mask rule %s", spec))
+		}
+		return func(value string, maskChar rune) string {
+			return maskKeep(value, head, tail, maskChar)
+		}, nil
+	}
+
+	fn, ok := lookupMasker(spec)
+	if !ok {
+		return nil, errors.New(fmt.Sprintf("unknown masker %s", spec))
+	}
+	return fn, nil
+}
+
+// maskChar return the rune of option `char=`
+func (tag csvTag) maskChar() (rune, error) {
+	value, ok := tag.option("char")
+	if !ok {
+		return defaultMaskChar, nil
+	}
+	if utf8.RuneCountInString(value) != 1 {
+		return 0, errors.New(fmt.Sprintf("mask char must be a single character: %q", value))
+	}
+	r, _ := utf8.DecodeRuneInString(value)
+	return r, nil
+}
+
+// maskKeep keep head characters at the beginning and tail characters at the end,the others are masked.
+// A value not longer than head+tail is returned as is.
+func maskKeep(value string, head, tail int, maskChar rune) string {
+	runes := []rune(value)
+	if len(runes) <= head+tail {
+		return value
+	}
+	return string(runes[:head]) + strings.Repeat(string(maskChar), len(runes)-head-tail) + string(runes[len(runes)-tail:])
+}
+
+// maskPhone keep the first 3 and the last 4 digits of an 11-digit mobile phone number,
+// a quarter of the digits are kept at both ends of a shorter number
+func maskPhone(value string, maskChar rune) string {
+	n := utf8.RuneCountInString(value)
+	if n >= 11 {
+		return maskKeep(value, 3, 4, maskChar)
+	}
+	return maskKeep(value, n/4, n/4, maskChar)
+}
+
+// maskEmail keep the first 2 and the last character of the username,the username must be longer than 3 characters
+func maskEmail(value string, maskChar rune) string {
+	emailSpl := strings.Split(value, "@")
+	if len(emailSpl) != 2 {
+		return value
+	}
+
+	user := []rune(emailSpl[0])
+	if len(user) <= 3 {
+		return value
+	}
+	return string(user[0:2]) + strings.Repeat(string(maskChar), 3) + string(user[len(user)-1:]) + "@" + emailSpl[1]
+}
+
+// maskIDCard keep the region code(first 6) and the last 4 characters of a Chinese ID card number
+func maskIDCard(value string, maskChar rune) string {
+	return maskKeep(value, 6, 4, maskChar)
+}
+
+// maskBankCard keep the issuer number(first 6) and the last 4 digits of a bank card number
+func maskBankCard(value string, maskChar rune) string {
+	return maskKeep(value, 6, 4, maskChar)
+}
+
+// maskName keep the first character of a name,and the last character if the name is longer than 2 characters
+func maskName(value string, maskChar rune) string {
+	n := utf8.RuneCountInString(value)
+	switch {
+	case n < 2:
+		return value
+	case n == 2:
+		return maskKeep(value, 1, 0, maskChar)
+	default:
+		return maskKeep(value, 1, 1, maskChar)
+	}
+}
+
+// maskAddress keep the first 6 characters(province and city) of an address,at most half of the address is kept
+func maskAddress(value string, maskChar rune) string {
+	head := utf8.RuneCountInString(value) / 2
+	if head > 6 {
+		head = 6
+	}
+	return maskKeep(value, head, 0, maskChar)
+}
+
+// maskIP mask the last 2 parts of an IPv4 address or the last 4 groups of an IPv6 address
+func maskIP(value string, maskChar rune) string {
+	ip := net.ParseIP(value)
+	if ip == nil {
+		return value
+	}
+
+	mask := string(maskChar)
+	if ip.To4() != nil {
+		parts := strings.Split(value, ".")
+		if len(parts) != 4 {
+			return value
+		}
+		return parts[0] + "." + parts[1] + "." + mask + "." + mask
+	}
+
+	//展开IPv6地址，保留前4组
+	ip = ip.To16()
+	groups := make([]string, 8)
+	for i := range groups {
+		if i < 4 {
+			groups[i] = strconv.FormatUint(uint64(ip[2*i])<<8|uint64(ip[2*i+1]), 16)
+		} else {
+			groups[i] = mask
+		}
+	}
+	return strings.Join(groups, ":")
+}
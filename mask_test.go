@@ -0,0 +1,127 @@
+package easy_csv
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestMaskers(t *testing.T) {
+	cases := []struct {
+		masker string
+		value  string
+		expect string
+	}{
+		{"phone", "13322226666", "133****6666"},
+		{"phone", "1331111", "1*****1"},
+		{"phone", "13311112", "13****12"},
+		{"email", "wangwu@qq.com", "wa***u@qq.com"},
+		{"email", "zh@qq.com", "zh@qq.com"},
+		{"idcard", "110101199003071234", "110101********1234"},
+		{"bankcard", "6222021234567890123", "622202*********0123"},
+		{"name", "张三", "张*"},
+		{"name", "王小明", "王*明"},
+		{"address", "北京市朝阳区建国路88号", "北京市朝阳区******"},
+		{"ip", "192.168.1.10", "192.168.*.*"},
+		{"ip", "2001:db8::1", "2001:db8:0:0:*:*:*:*"},
+	}
+
+	for _, c := range cases {
+		fn, err := parseMaskSpec(c.masker)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if v := fn(c.value, defaultMaskChar); v != c.expect {
+			t.Errorf("%s(%s): expect %s, got %s", c.masker, c.value, c.expect, v)
+		}
+	}
+}
+
+type testMaskBean struct {
+	Name  string `csv:"姓名,name_desensitization"`
+	Card  string `csv:"卡号,mask=keep(4,4),char=#"`
+	Plate string `csv:"车牌,plate_desensitization"`
+	Phone string `csv:"手机号,mask=phone"`
+}
+
+type testUnknownMaskBean struct {
+	Phone string `csv:"手机号,phone_desensitisation"`
+}
+
+func TestMarshalStructureMask(t *testing.T) {
+	RegisterMasker("plate", func(value string, maskChar rune) string {
+		return maskKeep(value, 2, 0, maskChar)
+	})
+
+	tb := testMaskBean{
+		Name:  "王小明",
+		Card:  "6222021234567890",
+		Plate: "京A12345",
+		Phone: "13322226666",
+	}
+	data, err := marshalStructure(tb, true, nil)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if strings.Join(data[1], ",") != "王*明,6222########7890,京A*****,133****6666" {
+		t.Errorf("unexpected data: %v", data)
+	}
+	t.Logf("有表头：%v\n", data)
+
+	_, err = marshalStructure(testUnknownMaskBean{Phone: "13322226666"}, false, nil)
+	if err == nil {
+		t.Error("unknown masking option should be an error")
+	}
+	t.Logf("error: %v\n", err)
+}
+
+func TestClientWriter_WriteRow2FileMaskPolicy(t *testing.T) {
+	row := testStudentInfo{
+		Name:  "李四",
+		Age:   10,
+		Grade: "4年级",
+		Score: 99.01,
+		Email: "lisi@qq.com",
+		Phone: "13322226666",
+	}
+
+	profiles := MaskProfiles{
+		"admin":  {DisableTags: true},
+		"vendor": {Columns: map[string]string{"name": "name", "邮箱": "none", "Phone": "keep(0,4)"}},
+	}
+
+	cases := map[string]string{
+		"admin":  "李四,10,4年级,99.01,lisi@qq.com,13322226666\n",
+		"vendor": "李*,10,4年级,99.01,lisi@qq.com,*******6666\n",
+	}
+
+	for role, expect := range cases {
+		buf := &strings.Builder{}
+		writer := NewClientWriter(buf, WithWriterMaskProfile(profiles, role))
+		err := writer.WriteRow2File(row)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if buf.String() != expect {
+			t.Errorf("%s: unexpected data: %s", role, buf.String())
+		}
+	}
+
+	//未知角色不能退回到结构体标签的脱敏规则
+	buf := &strings.Builder{}
+	err := NewClientWriter(buf, WithWriterMaskProfile(profiles, "vender")).WriteRow2File(row)
+	if !errors.Is(err, ErrUnknownMaskRole) || buf.Len() != 0 {
+		t.Errorf("unknown role should be an error, got %v %q", err, buf.String())
+	}
+	if _, err = profiles.Policy("vender"); !errors.Is(err, ErrUnknownMaskRole) {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	writer := NewClientWriter(&strings.Builder{}, WithWriterMaskPolicy(&MaskPolicy{Columns: map[string]string{"name": "unknown"}}))
+	if err := writer.WriteRow2File(row); err == nil {
+		t.Error("unknown masker of policy should be an error")
+	}
+}
@@ -0,0 +1,245 @@
+package easy_csv
+
+import (
+	"io"
+	"net"
+	"regexp"
+	"strings"
+)
+
+// PIIKind is a kind of personal information,it is also the name of the masker for it
+type PIIKind string
+
+const (
+	PIIPhone    PIIKind = "phone"
+	PIIEmail    PIIKind = "email"
+	PIIIDCard   PIIKind = "idcard"
+	PIIBankCard PIIKind = "bankcard"
+	PIIIP       PIIKind = "ip"
+)
+
+// piiKinds kinds in the order of detection,the former wins a tie of match ratio
+var piiKinds = []PIIKind{PIIPhone, PIIEmail, PIIIDCard, PIIBankCard, PIIIP}
+
+var (
+	phonePattern  = regexp.MustCompile(`^(\+?86[- ]?)?1[3-9]\d{9}$`)
+	emailPattern  = regexp.MustCompile(`^[^@\s]+@[^@\s]+\.[^@\s]+$`)
+	idCardPattern = regexp.MustCompile(`^\d{17}[\dXx]$`)
+	digitsPattern = regexp.MustCompile(`^\d{13,19}$`)
+)
+
+// PIIColumn is the scan result of a column
+type PIIColumn struct {
+	Index   int             // index of column
+	Name    string          // name of column in header,empty if the scan has no header
+	Values  int             // count of non-empty cells scanned
+	Matches map[PIIKind]int // count of cells matching every kind
+	Kind    PIIKind         // the detected kind,empty if no kind reaches the threshold
+	Ratio   float64         // match ratio of Kind
+}
+
+// MatchRatio return the ratio of non-empty cells matching kind
+func (column *PIIColumn) MatchRatio(kind PIIKind) float64 {
+	if column.Values == 0 {
+		return 0
+	}
+	return float64(column.Matches[kind]) / float64(column.Values)
+}
+
+// PIIReport is the result of ScanPII
+type PIIReport struct {
+	Rows    int          // count of data rows scanned
+	Columns []*PIIColumn // every column in order
+}
+
+// Detected return the columns whose values look like personal information
+func (report *PIIReport) Detected() []*PIIColumn {
+	columns := make([]*PIIColumn, 0)
+	for _, column := range report.Columns {
+		if len(column.Kind) > 0 {
+			columns = append(columns, column)
+		}
+	}
+	return columns
+}
+
+// MaskPolicy suggest a mask policy which masks every detected column with the masker of its kind.
+// It can be given to WithWriterMaskPolicy,the columns without header name are not included.
+func (report *PIIReport) MaskPolicy() *MaskPolicy {
+	policy := &MaskPolicy{Columns: make(map[string]string)}
+	for _, column := range report.Detected() {
+		if len(column.Name) > 0 {
+			policy.Columns[column.Name] = string(column.Kind)
+		}
+	}
+	return policy
+}
+
+// MaskRecord apply the masker of every detected column to a record,
+// so that the records read by ClientReader can be written masked by WriteString2File
+func (report *PIIReport) MaskRecord(record []string) []string {
+	masked := append([]string(nil), record...)
+	for _, column := range report.Detected() {
+		if column.Index >= len(masked) {
+			continue
+		}
+		fn, ok := lookupMasker(string(column.Kind))
+		if ok {
+			masked[column.Index] = fn(masked[column.Index], defaultMaskChar)
+		}
+	}
+	return masked
+}
+
+type PIIScanOption struct {
+	// Header the first record is the header,it is true by default
+	Header bool
+
+	// Threshold the minimum match ratio for a column to be detected,it is 0.8 by default
+	Threshold float64
+
+	// MaxRows the maximum count of data rows to scan,0 means all
+	MaxRows int
+}
+
+type PIIScanOptionFunc func(opt *PIIScanOption)
+
+func WithPIIHeader(header bool) PIIScanOptionFunc {
+	return func(opt *PIIScanOption) {
+		opt.Header = header
+	}
+}
+
+func WithPIIThreshold(threshold float64) PIIScanOptionFunc {
+	return func(opt *PIIScanOption) {
+		opt.Threshold = threshold
+	}
+}
+
+func WithPIIMaxRows(maxRows int) PIIScanOptionFunc {
+	return func(opt *PIIScanOption) {
+		opt.MaxRows = maxRows
+	}
+}
+
+// ScanPII scan the remaining records of reader,and report the columns whose values look like
+// phone numbers, emails, Chinese ID cards, bank cards or IP addresses
+func ScanPII(reader *ClientReader, opts ...PIIScanOptionFunc) (*PIIReport, error) {
+	option := &PIIScanOption{
+		Header:    true,
+		Threshold: 0.8,
+	}
+	for _, o := range opts {
+		o(option)
+	}
+
+	report := &PIIReport{Columns: make([]*PIIColumn, 0)}
+	column := func(i int) *PIIColumn {
+		for len(report.Columns) <= i {
+			report.Columns = append(report.Columns, &PIIColumn{
+				Index:   len(report.Columns),
+				Matches: make(map[PIIKind]int),
+			})
+		}
+		return report.Columns[i]
+	}
+
+	if option.Header {
+		header, err := reader.Read()
+		if err == io.EOF {
+			return report, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		for i, name := range header {
+			column(i).Name = name
+		}
+	}
+
+	for option.MaxRows <= 0 || report.Rows < option.MaxRows {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		report.Rows++
+
+		for i, cell := range record {
+			cell = strings.TrimSpace(cell)
+			if len(cell) == 0 {
+				continue
+			}
+
+			c := column(i)
+			c.Values++
+			for _, kind := range piiKinds {
+				if matchPII(kind, cell) {
+					c.Matches[kind]++
+				}
+			}
+		}
+	}
+
+	for _, c := range report.Columns {
+		for _, kind := range piiKinds {
+			ratio := c.MatchRatio(kind)
+			if ratio >= option.Threshold && ratio > c.Ratio {
+				c.Kind = kind
+				c.Ratio = ratio
+			}
+		}
+	}
+	return report, nil
+}
+
+func matchPII(kind PIIKind, value string) bool {
+	switch kind {
+	case PIIPhone:
+		return phonePattern.MatchString(value)
+	case PIIEmail:
+		return emailPattern.MatchString(value)
+	case PIIIDCard:
+		return idCardPattern.MatchString(value) && idCardChecksum(value)
+	case PIIBankCard:
+		return digitsPattern.MatchString(value) && luhn(value)
+	case PIIIP:
+		return strings.ContainsAny(value, ".:") && net.ParseIP(value) != nil
+	}
+	return false
+}
+
+// idCardChecksum verify the check digit of an 18-digit Chinese ID card number by ISO 7064 MOD 11-2
+func idCardChecksum(value string) bool {
+	weights := []int{7, 9, 10, 5, 8, 4, 2, 1, 6, 3, 7, 9, 10, 5, 8, 4, 2}
+	sum := 0
+	for i, w := range weights {
+		sum += int(value[i]-'0') * w
+	}
+	check := "10X98765432"[sum%11]
+	last := value[17]
+	if last == 'x' {
+		last = 'X'
+	}
+	return last == check
+}
+
+// luhn verify a number by the Luhn algorithm
+func luhn(value string) bool {
+	sum := 0
+	double := false
+	for i := len(value) - 1; i >= 0; i-- {
+		d := int(value[i] - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return sum%10 == 0
+}
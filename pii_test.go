@@ -0,0 +1,47 @@
+package easy_csv
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestScanPII(t *testing.T) {
+	data := "name,phone,mail,id,card,ip,note\n" +
+		"王五,13322226666,wangwu@qq.com,11010519491231002X,4111111111111111,192.168.1.10,a\n" +
+		"张三,13322225559,zh@qq.com,110101199003070011,6011000990139424,10.0.0.1,b\n" +
+		"李四,133****6666,li***i@qq.com,,5555555555554444,::1,c\n"
+
+	report, err := ScanPII(NewClientReader(strings.NewReader(data)))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	expect := []PIIKind{"", "", PIIEmail, PIIIDCard, PIIBankCard, PIIIP, ""}
+	for i, column := range report.Columns {
+		if column.Kind != expect[i] {
+			t.Errorf("column %s: expect %s, got %s %v", column.Name, expect[i], column.Kind, column.Matches)
+		}
+	}
+	t.Logf("rows: %d, detected: %d\n", report.Rows, len(report.Detected()))
+
+	//已脱敏的手机号不算命中，降低阈值后才能识别
+	report, err = ScanPII(NewClientReader(strings.NewReader(data)), WithPIIThreshold(0.6))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if report.Columns[1].Kind != PIIPhone {
+		t.Errorf("unexpected kind of phone: %s", report.Columns[1].Kind)
+	}
+
+	policy := report.MaskPolicy()
+	if policy.Columns["phone"] != "phone" || policy.Columns["card"] != "bankcard" {
+		t.Errorf("unexpected policy: %v", policy.Columns)
+	}
+
+	masked := report.MaskRecord([]string{"王五", "13322226666", "wangwu@qq.com", "", "4111111111111111", "10.0.0.1", "a"})
+	if strings.Join(masked, ",") != "王五,133****6666,wa***u@qq.com,,411111******1111,10.0.*.*,a" {
+		t.Errorf("unexpected masked record: %v", masked)
+	}
+}
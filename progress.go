@@ -0,0 +1,141 @@
+package easy_csv
+
+import (
+	"io"
+	"time"
+)
+
+// Stats are the processing statistics of a client
+type Stats struct {
+	Rows    int           // records read or written,the header is counted
+	Bytes   int64         // bytes consumed from the input,or written to the output
+	Errors  int           // rows rejected or failed
+	Elapsed time.Duration // time since the first record
+}
+
+// ProgressFunc receives the statistics of a client,
+// it is called by the goroutine reading or writing and should return quickly
+type ProgressFunc func(stats Stats)
+
+// ProgressOption decides when a ProgressFunc is called
+type ProgressOption struct {
+	Func ProgressFunc
+	// EveryRows call Func after this many rows since the last call,0 means no limit
+	EveryRows int
+	// Interval call Func when a row is processed this long after the last call,0 means no limit
+	Interval time.Duration
+}
+
+// WithReaderProgress call fn every everyRows rows or interval while reading,0 disables a condition
+func WithReaderProgress(fn ProgressFunc, everyRows int, interval time.Duration) ClientReaderOptionFunc {
+	return func(opt *ClientReaderOption) {
+		opt.Progress = &ProgressOption{Func: fn, EveryRows: everyRows, Interval: interval}
+	}
+}
+
+// WithWriterProgress call fn every everyRows rows or interval while writing,0 disables a condition
+func WithWriterProgress(fn ProgressFunc, everyRows int, interval time.Duration) ClientWriterOptionFunc {
+	return func(opt *ClientWriterOption) {
+		opt.Progress = &ProgressOption{Func: fn, EveryRows: everyRows, Interval: interval}
+	}
+}
+
+// ReadRowsFromFileStats is ReadRowsFromFile returning the final statistics of reader
+//
+// list: The parameter list is a list pointer,the item of list must be a structure or a structure pointer
+func (reader *ClientReader) ReadRowsFromFileStats(list interface{}) (Stats, error) {
+	err := reader.ReadRowsFromFile(list)
+	return reader.Stats(), err
+}
+
+// ReadRowsFromFileWithNamesStats is ReadRowsFromFileWithNames returning the final statistics of reader
+//
+// names: The parameter names is field names of structure in order to specify column of file to structure
+//
+// list: The parameter list is a list pointer,the item of list must be a structure or a structure pointer
+func (reader *ClientReader) ReadRowsFromFileWithNamesStats(names []string, list interface{}) (Stats, error) {
+	err := reader.ReadRowsFromFileWithNames(names, list)
+	return reader.Stats(), err
+}
+
+// WriteRows2FileStats is WriteRows2File returning the final statistics of writer
+//
+// list: The parameter list is a list pointer,the item of list must be a structure or a structure pointer
+func (writer *ClientWriter) WriteRows2FileStats(list interface{}, setTitle ...bool) (Stats, error) {
+	err := writer.WriteRows2File(list, setTitle...)
+	return writer.Stats(), err
+}
+
+// WriteString2FileStats is WriteString2File returning the final statistics of writer
+func (writer *ClientWriter) WriteString2FileStats(data [][]string) (Stats, error) {
+	err := writer.WriteString2File(data)
+	return writer.Stats(), err
+}
+
+// progressTracker counts the statistics of a client and calls the ProgressFunc
+type progressTracker struct {
+	option   *ProgressOption
+	stats    Stats
+	start    time.Time // time of the first row
+	end      time.Time // time of the last row
+	lastCall time.Time
+	lastRows int
+}
+
+func newProgressTracker(option *ProgressOption) *progressTracker {
+	if option == nil {
+		option = &ProgressOption{}
+	}
+	return &progressTracker{option: option}
+}
+
+// row count a processed row,bytes is the total bytes processed
+func (p *progressTracker) row(bytes int64) {
+	now := p.begin()
+	p.stats.Rows++
+	p.stats.Bytes = bytes
+
+	if p.option.Func == nil {
+		return
+	}
+	if (p.option.EveryRows > 0 && p.stats.Rows-p.lastRows >= p.option.EveryRows) ||
+		(p.option.Interval > 0 && now.Sub(p.lastCall) >= p.option.Interval) {
+		p.lastRows = p.stats.Rows
+		p.lastCall = now
+		p.option.Func(p.snapshot())
+	}
+}
+
+// fail count a rejected or failed row
+func (p *progressTracker) fail() {
+	p.begin()
+	p.stats.Errors++
+}
+
+func (p *progressTracker) begin() time.Time {
+	now := time.Now()
+	if p.start.IsZero() {
+		p.start = now
+		p.lastCall = now
+	}
+	p.end = now
+	return now
+}
+
+func (p *progressTracker) snapshot() Stats {
+	stats := p.stats
+	stats.Elapsed = p.end.Sub(p.start)
+	return stats
+}
+
+// countingWriter counts the bytes written to w
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,89 @@
+package easy_csv
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestReaderProgress(t *testing.T) {
+	data := "1,a\n2,b\nx,c\n4,d\n5,e\n"
+	calls := make([]Stats, 0)
+	reader := NewClientReader(strings.NewReader(data), WithReaderProgress(func(stats Stats) {
+		calls = append(calls, stats)
+	}, 2, 0))
+
+	for {
+		row := testBatchRow{}
+		if err := reader.ReadRowFromFile(&row); err == io.EOF {
+			break
+		}
+	}
+
+	if len(calls) != 2 || calls[0].Rows != 2 || calls[0].Bytes != 8 || calls[1].Rows != 4 {
+		t.Errorf("unexpected calls: %+v", calls)
+	}
+
+	stats := reader.Stats()
+	if stats.Rows != 5 || stats.Errors != 1 || stats.Bytes != int64(len(data)) {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+}
+
+func TestWriterProgress(t *testing.T) {
+	buf := &bytes.Buffer{}
+	calls := 0
+	writer := NewClientWriter(buf, WithWriterProgress(func(stats Stats) {
+		calls++
+	}, 1, 0))
+
+	list := []testBatchRow{{ID: 1, Name: "a"}, {ID: 2, Name: "b"}}
+	err := writer.WriteRows2File(list, true)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if calls != 3 {
+		t.Errorf("unexpected calls: %d", calls)
+	}
+	stats := writer.Stats()
+	if stats.Rows != 3 || stats.Bytes != int64(buf.Len()) || stats.Errors != 0 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+
+	writer = NewClientWriter(testFailWriter{})
+	_ = writer.WriteRows2File(list)
+	if writer.Stats().Errors != 1 {
+		t.Errorf("unexpected stats: %+v", writer.Stats())
+	}
+}
+
+func TestBulkStats(t *testing.T) {
+	list := make([]testBatchRow, 0)
+	reader := NewClientReader(strings.NewReader("1,a\n2,b\n"))
+	stats, err := reader.ReadRowsFromFileStats(&list)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if stats.Rows != 2 || stats.Bytes != 8 || len(list) != 2 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+
+	buf := &bytes.Buffer{}
+	stats, err = NewClientWriter(buf).WriteRows2FileStats(list, true)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if stats.Rows != 3 || stats.Bytes != int64(buf.Len()) {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+
+	stats, err = NewClientWriter(testFailWriter{}).WriteString2FileStats([][]string{{"1", "a"}})
+	if err == nil || stats.Errors != 1 {
+		t.Errorf("unexpected stats %+v: %v", stats, err)
+	}
+}
@@ -17,12 +17,20 @@ import (
 //		Phone string  `csv:"手机号,phone_desensitization"`
 //	}
 //
-// 'email_desensitization' will desensitize the email ,and  'phone_desensitization' will desensitize the phone number
+// 'email_desensitization' will desensitize the email ,and  'phone_desensitization' will desensitize the phone number.
+// Every masker registered by RegisterMasker can be used the same way,or as `mask=<name>`,
+// and `mask=keep(3,4),char=#` keeps the first 3 and the last 4 characters and hides the others with '#'.
+// An unknown option of tag is an error.
+//
+// 'tokenize' replaces the value with a keyed HMAC token and 'encrypt' encrypts the value,
+// the key is given by the option of writer.
 //
 // bean interface{}:  a structure or a pointer of structure，every field of struct should be convertible to type string
 //
 // setTitle bool:  true: the index 0 in result will be set column name
-func marshalStructure(bean interface{}, setTitle bool) ([][]string, error) {
+//
+// option *ClientWriterOption: keys of 'tokenize' and 'encrypt' and the selected columns,nil means no key and all columns
+func marshalStructure(bean interface{}, setTitle bool, option *ClientWriterOption) ([][]string, error) {
 
 	if bean == nil {
 		return [][]string{}, nil
@@ -47,66 +55,34 @@ func marshalStructure(bean interface{}, setTitle bool) ([][]string, error) {
 		reflectType = reflectType.Elem()
 	}
 
-	numField := reflectValue.NumField()
+	columns, err := columnFields(reflectType, option)
+	if err != nil {
+		return [][]string{}, err
+	}
 
 	//行数据
-	rowData := make([]string, numField)
+	rowData := make([]string, 0, len(columns))
 	//表头
-	title := make([]string, numField)
+	title := make([]string, 0, len(columns))
 
 	//结构体每一个参数必须可以转换成字符串
-	for i := 0; i < numField; i++ {
-		field := reflectValue.Field(i)
-		fieldType := reflectType.Field(i)
+	for _, column := range columns {
+		field := reflectValue.FieldByIndex(column.fieldType.Index)
 
 		//if !field.CanConvert(strType) {
 		//	return [][]string{}, errors.New("All structure field should be convertible to type string")
 		//}
 
-		rowData[i] = fmt.Sprint(field.Interface())
-
-		tagStr := fieldType.Tag.Get("csv")
-		if len(tagStr) == 0 {
-			if setTitle {
-				title[i] = reflectType.Field(i).Name
-			}
-			continue
-		}
-
-		tagStrSpl := strings.Split(tagStr, ",")
 		if setTitle {
-			title[i] = tagStrSpl[0]
+			title = append(title, column.title)
 		}
 
-		if len(tagStrSpl) > 1 {
-			format := tagStrSpl[1]
-
-			if format == "phone_desensitization" {
-				//手机号脱敏
-				phone := []rune(rowData[i])
-
-				if len(phone) > 6 {
-					rowData[i] = string(phone[0:3]) + "****" + string(phone[7:])
-				}
-			}
-
-			if format == "email_desensitization" {
-				//邮箱脱敏
-				email := rowData[i]
-
-				emailSpl := strings.Split(email, "@")
-				if len(emailSpl) == 2 {
-					user := []rune(emailSpl[0])
-					domain := emailSpl[1]
-
-					if len(user) > 3 {
-						userStr := string(user[0:2]) + "***" + string(user[len(user)-1:])
-						rowData[i] = userStr + "@" + domain
-					}
-				}
-			}
+		cell, err := column.tag.encode(formatValue(field.Interface()), column.tag.columnName(column.fieldType), column.fieldType.Name, option)
+		if err != nil {
+			return [][]string{}, fmt.Errorf("field %s: %w", column.fieldType.Name, err)
 		}
 
+		rowData = append(rowData, cell)
 	}
 
 	if !setTitle {
@@ -121,7 +97,9 @@ func marshalStructure(bean interface{}, setTitle bool) ([][]string, error) {
 // list interface{}: any item of list should be a structure or a pointer of structure，every field of struct should be convertible to type string
 //
 // setTitle bool : true: the index 0 in result will be set column name
-func marshalList(list interface{}, setTitle bool) ([][]string, error) {
+//
+// option *ClientWriterOption: keys of 'tokenize' and 'encrypt' and the selected columns,nil means no key and all columns
+func marshalList(list interface{}, setTitle bool, option *ClientWriterOption) ([][]string, error) {
 	if list == nil {
 		return [][]string{}, nil
 	}
@@ -169,7 +147,7 @@ func marshalList(list interface{}, setTitle bool) ([][]string, error) {
 		}
 
 		if i == 0 {
-			rows, err := marshalStructure(bean, setTitle)
+			rows, err := marshalStructure(bean, setTitle, option)
 			if err != nil {
 				return [][]string{}, errors.New(fmt.Sprintf("err:%+v ,invalid row data: %v", err, bean))
 			}
@@ -178,7 +156,7 @@ func marshalList(list interface{}, setTitle bool) ([][]string, error) {
 		}
 
 		if i > 0 {
-			rows, err := marshalStructure(bean, false)
+			rows, err := marshalStructure(bean, false, option)
 			if err != nil {
 				return [][]string{}, errors.New(fmt.Sprintf("err:%+v ,invalid row data: %v", err, bean))
 			}
@@ -196,7 +174,11 @@ func marshalList(list interface{}, setTitle bool) ([][]string, error) {
 // source []string: a one-dimensional slice
 //
 // target interface{}: a pointer of structure
-func unmarshalOneDSlice(source []string, target interface{}) error {
+//
+// option *ClientReaderOption: decides how empty or missing cells are handled, nil means the default policy
+//
+// meta *rowMeta: provenance of source filled to the metadata fields, nil means unknown
+func unmarshalOneDSlice(source []string, target interface{}, option *ClientReaderOption, meta *rowMeta) error {
 
 	if target == nil {
 		return errors.New("target cannot be nil")
@@ -214,23 +196,45 @@ func unmarshalOneDSlice(source []string, target interface{}) error {
 	if reflectValue.Kind() != reflect.Struct {
 		return errors.New("target must be a pointer of structure")
 	}
+	reflectType := reflectValue.Type()
 	fieldNum := reflectValue.NumField()
 
+	//列序号，overflow字段和元数据字段不占用列
+	column := 0
+	overflow := -1
 	for i := 0; i < fieldNum; i++ {
 		field := reflectValue.Field(i)
+		fieldType := reflectType.Field(i)
 
-		if i < sourceLen {
-			s := source[i]
-			err := setFieldValue(field, s)
-			if err != nil {
-				return err
+		tag := parseTag(fieldType)
+		if !tag.isColumn() {
+			if tag.isOverflow() {
+				overflow = i
 			}
-		} else {
-			break
+			continue
+		}
+
+		//短行缺失的列按空单元格处理
+		s := ""
+		if column < sourceLen {
+			s = source[column]
+		}
+		column++
+
+		err := setCellValue(field, fieldType, s, option)
+		if err != nil {
+			return err
 		}
 	}
 
-	return nil
+	if overflow >= 0 && column < sourceLen {
+		err := setOverflowValue(reflectValue.Field(overflow), reflectType.Field(overflow), source[column:])
+		if err != nil {
+			return err
+		}
+	}
+
+	return setMetaValues(reflectValue, meta)
 }
 
 // unmarshal a two-dimensional slice to a list,the list must be a pointer of list,and the item of list must be a structure or a pointer of a structure.
@@ -238,7 +242,11 @@ func unmarshalOneDSlice(source []string, target interface{}) error {
 // source [][]string:a two-dimensional slice pointer  of a list
 //
 // target interface{}: a pointer of a list
-func unmarshalTwoDSlice(source [][]string, target interface{}) error {
+//
+// option *ClientReaderOption: decides how empty or missing cells are handled, nil means the default policy
+//
+// metas []*rowMeta: provenance of every row of source, nil means unknown
+func unmarshalTwoDSlice(source [][]string, target interface{}, option *ClientReaderOption, metas []*rowMeta) error {
 	if target == nil {
 		return errors.New("target can't is nil")
 	}
@@ -262,7 +270,7 @@ func unmarshalTwoDSlice(source [][]string, target interface{}) error {
 
 	itemReflectType := reflectSliType.Elem()
 
-	for _, row := range source {
+	for i, row := range source {
 
 		var subTarget reflect.Value
 		if itemReflectType.Kind() == reflect.Pointer {
@@ -278,7 +286,7 @@ func unmarshalTwoDSlice(source [][]string, target interface{}) error {
 
 		var err error
 
-		err = unmarshalOneDSlice(row, subTarget.Interface())
+		err = unmarshalOneDSlice(row, subTarget.Interface(), option, metaAt(metas, i))
 
 		if err != nil {
 			return err
@@ -305,7 +313,11 @@ func unmarshalTwoDSlice(source [][]string, target interface{}) error {
 // source []string: a one-dimensional slice
 //
 // target interface{}: a pointer of structure
-func unmarshalOneDSliceWithNames(names []string, source []string, target interface{}) error {
+//
+// option *ClientReaderOption: decides how empty or missing cells are handled, nil means the default policy
+//
+// meta *rowMeta: provenance of source filled to the metadata fields, nil means unknown
+func unmarshalOneDSliceWithNames(names []string, source []string, target interface{}, option *ClientReaderOption, meta *rowMeta) error {
 
 	if target == nil {
 		return errors.New("target can't is nil")
@@ -314,9 +326,6 @@ func unmarshalOneDSliceWithNames(names []string, source []string, target interfa
 	if len(names) == 0 || len(source) == 0 {
 		return errors.New("titles and source must have a value")
 	}
-	if len(names) != len(source) {
-		return errors.New("titles and source must have same length")
-	}
 
 	reflectValue := reflect.ValueOf(target)
 
@@ -329,20 +338,39 @@ func unmarshalOneDSliceWithNames(names []string, source []string, target interfa
 	}
 
 	reflectValue = reflectValue.Elem()
+	reflectType := reflectValue.Type()
+
+	if len(names) < len(source) {
+		//长行多出的列写入overflow字段，非严格模式下没有overflow字段则忽略
+		overflow, ok := overflowField(reflectType)
+		if ok {
+			err := setOverflowValue(reflectValue.FieldByIndex(overflow.Index), overflow, source[len(names):])
+			if err != nil {
+				return err
+			}
+		} else if option == nil || option.RaggedPolicy == RaggedStrict {
+			return errors.New("titles and source must have same length")
+		}
+	}
 
 	for i := 0; i < len(names); i++ {
 		t := names[i]
-		s := source[i]
 
-		field := reflectValue.FieldByName(t)
-		if field.Kind() != reflect.Invalid {
-			err := setFieldValue(field, s)
+		//短行缺失的列按空单元格处理
+		s := ""
+		if i < len(source) {
+			s = source[i]
+		}
+
+		fieldType, ok := reflectType.FieldByName(t)
+		if ok && parseTag(fieldType).isColumn() {
+			err := setCellValue(reflectValue.FieldByIndex(fieldType.Index), fieldType, s, option)
 			if err != nil {
 				return err
 			}
 		}
 	}
-	return nil
+	return setMetaValues(reflectValue, meta)
 
 }
 
@@ -353,7 +381,11 @@ func unmarshalOneDSliceWithNames(names []string, source []string, target interfa
 // source [][]string:a two-dimensional slice pointer  of a list
 //
 // target interface{}: a pointer of a list
-func unmarshalTwoDSliceWithNames(names []string, source [][]string, target interface{}) error {
+//
+// option *ClientReaderOption: decides how empty or missing cells are handled, nil means the default policy
+//
+// metas []*rowMeta: provenance of every row of source, nil means unknown
+func unmarshalTwoDSliceWithNames(names []string, source [][]string, target interface{}, option *ClientReaderOption, metas []*rowMeta) error {
 
 	if target == nil {
 		return errors.New("target can't is nil")
@@ -376,7 +408,7 @@ func unmarshalTwoDSliceWithNames(names []string, source [][]string, target inter
 	}
 
 	itemReflectType := reflectSliType.Elem()
-	for _, row := range source {
+	for i, row := range source {
 		var subTarget reflect.Value
 		if itemReflectType.Kind() == reflect.Ptr {
 			subTarget = reflect.New(itemReflectType.Elem())
@@ -390,7 +422,7 @@ func unmarshalTwoDSliceWithNames(names []string, source [][]string, target inter
 		}
 
 		var err error
-		err = unmarshalOneDSliceWithNames(names, row, subTarget.Interface())
+		err = unmarshalOneDSliceWithNames(names, row, subTarget.Interface(), option, metaAt(metas, i))
 
 		if err != nil {
 			return err
@@ -407,6 +439,410 @@ func unmarshalTwoDSliceWithNames(names []string, source [][]string, target inter
 	return nil
 }
 
+// setCellValue set a cell of csv to the field.
+// The transform options of tag run first,then an empty cell is resolved by the EmptyPolicy of option before it is converted,
+// the default policy takes the value of the `default=` tag option if the field has one.
+func setCellValue(field reflect.Value, fieldType reflect.StructField, str string, option *ClientReaderOption) error {
+	tag := parseTag(fieldType)
+
+	//加密字段先解密，没有密钥时保持密文
+	if tag.has("encrypt") && len(str) > 0 && option != nil && option.KeyRing != nil {
+		plain, err := option.KeyRing.Decrypt(str)
+		if err != nil {
+			return fmt.Errorf("field %s: %w", fieldType.Name, err)
+		}
+		str = plain
+	}
+
+	str = tag.transform(str)
+	if len(str) > 0 {
+		return setFieldValue(field, str)
+	}
+
+	policy := EmptyDefault
+	if option != nil {
+		policy = option.EmptyPolicy
+	}
+
+	switch policy {
+	case EmptyDefault:
+		if def, ok := tag.option("default"); ok {
+			str = def
+		}
+	case EmptyZero:
+		if isNumericKind(field.Kind()) {
+			field.Set(reflect.Zero(field.Type()))
+			return nil
+		}
+	}
+
+	if len(str) == 0 && isNumericKind(field.Kind()) {
+		return errors.New(fmt.Sprintf("empty value for numeric field %s", fieldType.Name))
+	}
+	return setFieldValue(field, str)
+}
+
+// setOverflowValue set the cells beyond the columns of structure to the field tagged `csv:",overflow"`
+func setOverflowValue(field reflect.Value, fieldType reflect.StructField, cells []string) error {
+	if field.Type() != reflect.TypeOf([]string{}) {
+		return errors.New(fmt.Sprintf("overflow field %s must be []string", fieldType.Name))
+	}
+	field.Set(reflect.ValueOf(append([]string(nil), cells...)))
+	return nil
+}
+
+// formatValue format a value as a cell,nil and nil pointers are empty cells and pointers are dereferenced
+func formatValue(v interface{}) string {
+	if v == nil {
+		return ""
+	}
+
+	reflectValue := reflect.ValueOf(v)
+	for reflectValue.Kind() == reflect.Pointer || reflectValue.Kind() == reflect.Interface {
+		if reflectValue.IsNil() {
+			return ""
+		}
+		reflectValue = reflectValue.Elem()
+	}
+	return fmt.Sprint(reflectValue.Interface())
+}
+
+// structureTitle return the column names of a structure type in order.
+// t can be a structure,a pointer of structure,or a slice of them,nil is returned for other types.
+//
+// option *ClientWriterOption: the selected columns,nil means all columns
+func structureTitle(t reflect.Type, option *ClientWriterOption) []string {
+	if t == nil {
+		return nil
+	}
+	for t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	columns, err := columnFields(t, option)
+	if err != nil {
+		return nil
+	}
+
+	title := make([]string, 0, len(columns))
+	for _, column := range columns {
+		title = append(title, column.title)
+	}
+	return title
+}
+
+// columnField is a field of structure written as a column
+type columnField struct {
+	fieldType reflect.StructField
+	tag       csvTag
+	title     string
+}
+
+// columnFields return the fields of a structure type written as columns in order.
+// The Columns of option select the fields and their order,and override the titles.
+func columnFields(reflectType reflect.Type, option *ClientWriterOption) ([]columnField, error) {
+	if option == nil || len(option.Columns) == 0 {
+		columns := make([]columnField, 0, reflectType.NumField())
+		for i := 0; i < reflectType.NumField(); i++ {
+			fieldType := reflectType.Field(i)
+			tag := parseTag(fieldType)
+			if tag.isColumn() {
+				columns = append(columns, columnField{fieldType: fieldType, tag: tag, title: tag.columnName(fieldType)})
+			}
+		}
+		return columns, nil
+	}
+
+	columns := make([]columnField, 0, len(option.Columns))
+	for _, spec := range option.Columns {
+		fieldType, ok := fieldOfColumn(reflectType, spec.Field)
+		if !ok {
+			return nil, errors.New(fmt.Sprintf("unknown column %s of %s", spec.Field, reflectType.Name()))
+		}
+
+		tag := parseTag(fieldType)
+		title := spec.Title
+		if len(title) == 0 {
+			title = tag.columnName(fieldType)
+		}
+		columns = append(columns, columnField{fieldType: fieldType, tag: tag, title: title})
+	}
+	return columns, nil
+}
+
+// fieldOfColumn find a column field by field name or by column name
+func fieldOfColumn(reflectType reflect.Type, name string) (reflect.StructField, bool) {
+	fieldType, ok := reflectType.FieldByName(name)
+	if ok && parseTag(fieldType).isColumn() {
+		return fieldType, true
+	}
+
+	for i := 0; i < reflectType.NumField(); i++ {
+		fieldType = reflectType.Field(i)
+		tag := parseTag(fieldType)
+		if tag.isColumn() && tag.columnName(fieldType) == name {
+			return fieldType, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+// rowMeta is the provenance of a record read by ClientReader
+type rowMeta struct {
+	line   int      // line number of the first field,starting at 1
+	offset int64    // byte offset of the start of record
+	raw    []string // the record as read,before any policy applies
+}
+
+func metaAt(metas []*rowMeta, i int) *rowMeta {
+	if i < len(metas) {
+		return metas[i]
+	}
+	return nil
+}
+
+// setMetaValues fill the metadata fields tagged `csv:",line"`, `csv:",offset"` or `csv:",raw"` from meta
+func setMetaValues(reflectValue reflect.Value, meta *rowMeta) error {
+	if meta == nil {
+		return nil
+	}
+
+	reflectType := reflectValue.Type()
+	for i := 0; i < reflectType.NumField(); i++ {
+		fieldType := reflectType.Field(i)
+		field := reflectValue.Field(i)
+
+		tag := parseTag(fieldType)
+		switch {
+		case tag.has("line"):
+			if !field.CanInt() {
+				return errors.New(fmt.Sprintf("line field %s must be an integer", fieldType.Name))
+			}
+			field.SetInt(int64(meta.line))
+		case tag.has("offset"):
+			if !field.CanInt() {
+				return errors.New(fmt.Sprintf("offset field %s must be an integer", fieldType.Name))
+			}
+			field.SetInt(meta.offset)
+		case tag.has("raw"):
+			if field.Type() != reflect.TypeOf([]string{}) {
+				return errors.New(fmt.Sprintf("raw field %s must be []string", fieldType.Name))
+			}
+			field.Set(reflect.ValueOf(append([]string(nil), meta.raw...)))
+		}
+	}
+	return nil
+}
+
+// overflowField return the field tagged `csv:",overflow"` of structure
+func overflowField(reflectType reflect.Type) (reflect.StructField, bool) {
+	for i := 0; i < reflectType.NumField(); i++ {
+		fieldType := reflectType.Field(i)
+		if parseTag(fieldType).isOverflow() {
+			return fieldType, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+func isNumericKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// csvTag is the parsed form of a struct tag such as `csv:"qty,default=1"`
+type csvTag struct {
+	name    string      // column name,the part before the first comma
+	options []tagOption // options after the column name in order
+}
+
+// tagOption is an option of csv tag,like 'phone_desensitization' or 'default=1'
+type tagOption struct {
+	key   string
+	value string
+}
+
+func parseTag(fieldType reflect.StructField) csvTag {
+	tagStr := fieldType.Tag.Get("csv")
+	if len(tagStr) == 0 {
+		return csvTag{}
+	}
+
+	tagStrSpl := splitTag(tagStr)
+	return newTag(tagStrSpl[0], tagStrSpl[1:])
+}
+
+// newTag create a tag from a column name and options like 'trim' or 'default=1'
+func newTag(name string, options []string) csvTag {
+	tag := csvTag{name: name}
+	for _, s := range options {
+		if len(s) == 0 {
+			continue
+		}
+		key, value, _ := strings.Cut(s, "=")
+		tag.options = append(tag.options, tagOption{key: key, value: value})
+	}
+	return tag
+}
+
+// splitTag split a tag by comma,the commas of a masking rule like `mask=keep(3,4)` are kept.
+// Parentheses elsewhere,like in the argument of `replace=`,do not change the splitting.
+func splitTag(tagStr string) []string {
+	parts := make([]string, 0)
+	start := 0
+	for i := 0; i < len(tagStr); i++ {
+		switch tagStr[i] {
+		case '(':
+			if end := keepRuleEnd(tagStr, start, i); end > 0 {
+				i = end
+			}
+		case ',':
+			parts = append(parts, tagStr[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, tagStr[start:])
+}
+
+// keepRuleEnd return the index of the ')' closing the rule 'keep(' whose '(' is at open,
+// 0 if the option starting at start is not a complete rule of numbers
+func keepRuleEnd(tagStr string, start, open int) int {
+	option := tagStr[start:open]
+	if option != "keep" && option != "mask=keep" {
+		return 0
+	}
+
+	for i := open + 1; i < len(tagStr); i++ {
+		switch c := tagStr[i]; {
+		case c == ')':
+			return i
+		case c != ',' && c != ' ' && (c < '0' || c > '9'):
+			return 0
+		}
+	}
+	return 0
+}
+
+// columnName the column name in csv file,struct field name will be used if tag has no name
+func (tag csvTag) columnName(fieldType reflect.StructField) string {
+	if len(tag.name) == 0 {
+		return fieldType.Name
+	}
+	return tag.name
+}
+
+// tagKeywords options of csv tag which are neither transforms nor maskers
+var tagKeywords = map[string]bool{
+	"default":  true,
+	"overflow": true,
+	"line":     true,
+	"offset":   true,
+	"raw":      true,
+	"char":     true,
+}
+
+// encode run the options of tag on a formatted value before it is written,
+// transforms, maskers, 'tokenize' and 'encrypt' run in the order of options.
+// The MaskPolicy of option overrides the maskers of tag,a masker given by the policy runs after all options.
+// An unknown option is an error.
+//
+// column and field are the names to find the column in MaskPolicy
+func (tag csvTag) encode(value string, column string, field string, option *ClientWriterOption) (string, error) {
+	maskChar, err := tag.maskChar()
+	if err != nil {
+		return "", err
+	}
+
+	policy := option.maskPolicy()
+	override, overridden, err := policy.masker(column, field)
+	if err != nil {
+		return "", err
+	}
+
+	for _, opt := range tag.options {
+		if fn, ok := lookupTransform(opt.key); ok {
+			value = fn(value, opt.value)
+			continue
+		}
+
+		masker, ok, err := maskerOf(opt)
+		if err != nil {
+			return "", err
+		}
+		if ok {
+			if !overridden && !policy.tagsDisabled() {
+				value = masker(value, maskChar)
+			}
+			continue
+		}
+
+		switch opt.key {
+		case "tokenize":
+			if option == nil || len(option.TokenKey) == 0 {
+				return "", errors.New("tokenize requires a token key of writer")
+			}
+			if len(value) > 0 {
+				value = tokenize(value, option.TokenKey)
+			}
+			continue
+		case "encrypt":
+			if option == nil || option.KeyRing == nil {
+				return "", errors.New("encrypt requires a key ring of writer")
+			}
+			if len(value) > 0 {
+				value, err = option.KeyRing.Encrypt(value)
+				if err != nil {
+					return "", err
+				}
+			}
+			continue
+		}
+
+		if !tagKeywords[opt.key] {
+			return "", errors.New(fmt.Sprintf("unknown csv tag option %s", opt.key))
+		}
+	}
+
+	if override != nil {
+		value = override(value, maskChar)
+	}
+	return value, nil
+}
+
+// isOverflow report whether the field captures the overflow cells of long rows
+func (tag csvTag) isOverflow() bool {
+	return tag.has("overflow")
+}
+
+// isColumn report whether the field is a column of csv,overflow and metadata fields are not
+func (tag csvTag) isColumn() bool {
+	return !tag.has("overflow") && !tag.has("line") && !tag.has("offset") && !tag.has("raw")
+}
+
+// has report whether the tag has an option named key
+func (tag csvTag) has(key string) bool {
+	_, ok := tag.option(key)
+	return ok
+}
+
+// option return the value of the first option named key
+func (tag csvTag) option(key string) (string, bool) {
+	for _, opt := range tag.options {
+		if opt.key == key {
+			return opt.value, true
+		}
+	}
+	return "", false
+}
+
 func setFieldValue(field reflect.Value, str string) error {
 	t := field.Type()
 	switch t.Kind() {
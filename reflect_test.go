@@ -1,6 +1,7 @@
 package easy_csv
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -24,14 +25,14 @@ func TestMarshalStructure(t *testing.T) {
 		Phone: "1331111",
 	}
 
-	data, err := marshalStructure(&tb, true)
+	data, err := marshalStructure(&tb, true, nil)
 	if err != nil {
 		t.Error(err)
 		return
 	}
 	t.Logf("有表头：%v\n", data)
 
-	data, err = marshalStructure(tb, false)
+	data, err = marshalStructure(tb, false, nil)
 	if err != nil {
 		t.Error(err)
 		return
@@ -59,14 +60,14 @@ func TestMarshalList(t *testing.T) {
 		},
 	}
 
-	data, err := marshalList(list, true)
+	data, err := marshalList(list, true, nil)
 	if err != nil {
 		t.Error(err)
 		return
 	}
 	t.Logf("有表头：%v\n", data)
 
-	data, err = marshalList(&list, false)
+	data, err = marshalList(&list, false, nil)
 	if err != nil {
 		t.Error(err)
 		return
@@ -84,14 +85,14 @@ func TestMarshalList(t *testing.T) {
 	list2 := make([]*testBean, 1)
 	list2[0] = &tb
 
-	data, err = marshalList(list2, true)
+	data, err = marshalList(list2, true, nil)
 	if err != nil {
 		t.Error(err)
 		return
 	}
 	t.Logf("有表头：%v\n", data)
 
-	data, err = marshalList(&list2, false)
+	data, err = marshalList(&list2, false, nil)
 	if err != nil {
 		t.Error(err)
 		return
@@ -110,7 +111,7 @@ func TestUnmarshalOneDSlice(t *testing.T) {
 		"1331111",
 	}
 
-	err := unmarshalOneDSlice(source, &tb)
+	err := unmarshalOneDSlice(source, &tb, nil, nil)
 	if err != nil {
 		t.Error(err)
 		return
@@ -141,7 +142,7 @@ func TestUnmarshalTwoDSlice(t *testing.T) {
 		},
 	}
 	t.Logf("%p\n", &list)
-	err := unmarshalTwoDSlice(source, &list)
+	err := unmarshalTwoDSlice(source, &list, nil, nil)
 	if err != nil {
 		t.Error(err)
 		return
@@ -173,7 +174,7 @@ func TestUnmarshalOneDSliceWithNames(t *testing.T) {
 
 	tb := testBean{}
 
-	err := unmarshalOneDSliceWithNames(names, source, &tb)
+	err := unmarshalOneDSliceWithNames(names, source, &tb, nil, nil)
 	if err != nil {
 		t.Error(err)
 		return
@@ -214,7 +215,7 @@ func TestUnmarshalTwoDSliceWithNames(t *testing.T) {
 	}
 
 	t.Logf("%p\n", &list)
-	err := unmarshalTwoDSliceWithNames(names, source, &list)
+	err := unmarshalTwoDSliceWithNames(names, source, &list, nil, nil)
 	if err != nil {
 		t.Error(err)
 		return
@@ -224,7 +225,7 @@ func TestUnmarshalTwoDSliceWithNames(t *testing.T) {
 
 	list2 := make([]*testBean, 0)
 
-	err = unmarshalTwoDSliceWithNames(names, source, &list2)
+	err = unmarshalTwoDSliceWithNames(names, source, &list2, nil, nil)
 	if err != nil {
 		t.Error(err)
 		return
@@ -233,3 +234,61 @@ func TestUnmarshalTwoDSliceWithNames(t *testing.T) {
 
 	return
 }
+
+type testDefaultBean struct {
+	Name  string  `csv:"name,default=unknown"`
+	Qty   int     `csv:"qty,default=1"`
+	Price float64 `csv:"price"`
+}
+
+func TestUnmarshalOneDSliceDefault(t *testing.T) {
+	tb := testDefaultBean{}
+	err := unmarshalOneDSlice([]string{"", "", "1.5"}, &tb, nil, nil)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if tb.Name != "unknown" || tb.Qty != 1 || tb.Price != 1.5 {
+		t.Errorf("unexpected default value: %+v", tb)
+	}
+
+	//短行缺少价格列，价格没有默认值
+	tb = testDefaultBean{}
+	err = unmarshalOneDSlice([]string{"apple"}, &tb, nil, nil)
+	if err == nil {
+		t.Errorf("empty numeric field without default should be an error: %+v", tb)
+	}
+
+	tb = testDefaultBean{}
+	err = unmarshalOneDSlice([]string{"apple"}, &tb, &ClientReaderOption{EmptyPolicy: EmptyZero}, nil)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if tb.Qty != 0 || tb.Price != 0 {
+		t.Errorf("unexpected zero value: %+v", tb)
+	}
+
+	tb = testDefaultBean{}
+	err = unmarshalOneDSlice([]string{"apple", "", "2"}, &tb, &ClientReaderOption{EmptyPolicy: EmptyError}, nil)
+	if err == nil {
+		t.Errorf("empty numeric field should be an error: %+v", tb)
+	}
+	t.Logf("interface: %+v\n", tb)
+}
+
+func TestSplitTag(t *testing.T) {
+	cases := map[string][]string{
+		"卡号,mask=keep(4,4),char=#": {"卡号", "mask=keep(4,4)", "char=#"},
+		"x,keep(3, 4)":             {"x", "keep(3, 4)"},
+		"x,replace=(:[,trim":       {"x", "replace=(:[", "trim"},
+		"x,replace=(:),upper":      {"x", "replace=(:)", "upper"},
+		"x,mask=keep(3,trim":       {"x", "mask=keep(3", "trim"},
+	}
+	for tag, expect := range cases {
+		parts := splitTag(tag)
+		if strings.Join(parts, "|") != strings.Join(expect, "|") {
+			t.Errorf("%s: unexpected parts %q", tag, parts)
+		}
+	}
+}
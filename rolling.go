@@ -0,0 +1,263 @@
+package easy_csv
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+)
+
+// ManifestFile is a file written by RollingWriter
+type ManifestFile struct {
+	Path  string `json:"path"`
+	Rows  int    `json:"rows"`  // data rows,the header is not counted
+	Bytes int64  `json:"bytes"` // size of the file
+}
+
+// Manifest lists the files written by RollingWriter in order
+type Manifest struct {
+	Files []ManifestFile `json:"files"`
+}
+
+type RollingOption struct {
+	// MaxRows the maximum data rows of a file,0 means no limit
+	MaxRows int
+
+	// MaxBytes the maximum size of a file,0 means no limit.
+	// A row is never split,a file exceeds the limit only if its header and first row do.
+	MaxBytes int64
+
+	// Header the header repeated at the beginning of every file.
+	// If it is nil,the header of the structure written first is used,and rows written by WriteString2File have no header.
+	Header []string
+
+	// WriterOptions options of the ClientWriter of every file
+	WriterOptions []ClientWriterOptionFunc
+}
+
+type RollingOptionFunc func(opt *RollingOption)
+
+func WithRollingMaxRows(maxRows int) RollingOptionFunc {
+	return func(opt *RollingOption) {
+		opt.MaxRows = maxRows
+	}
+}
+
+func WithRollingMaxBytes(maxBytes int64) RollingOptionFunc {
+	return func(opt *RollingOption) {
+		opt.MaxBytes = maxBytes
+	}
+}
+
+func WithRollingHeader(header []string) RollingOptionFunc {
+	return func(opt *RollingOption) {
+		opt.Header = header
+	}
+}
+
+func WithRollingWriterOptions(opts ...ClientWriterOptionFunc) RollingOptionFunc {
+	return func(opt *RollingOption) {
+		opt.WriterOptions = opts
+	}
+}
+
+// RollingWriter writes rows to a sequence of files,and starts a new file when a limit is reached.
+// The header is repeated in every file.
+type RollingWriter struct {
+	pattern string
+	option  *RollingOption
+	header  []string
+
+	// scratch formats rows with the options of writer,so that the size of a row is known before it is written
+	scratch    *ClientWriter
+	scratchBuf *bytes.Buffer
+
+	file     *os.File
+	buffered *bufio.Writer
+	current  *ManifestFile
+	manifest *Manifest
+	closed   bool
+}
+
+// NewRollingWriter create a rolling writer.
+//
+// pattern: the path of files with a verb for the sequence number starting at 1,like "export_%03d.csv"
+func NewRollingWriter(pattern string, opts ...RollingOptionFunc) (*RollingWriter, error) {
+	if path := fmt.Sprintf(pattern, 1); strings.Contains(path, "%!") || path == fmt.Sprintf(pattern, 2) {
+		return nil, errors.New(fmt.Sprintf("pattern %s has no verb for the sequence number", pattern))
+	}
+
+	option := &RollingOption{}
+	for _, o := range opts {
+		o(option)
+	}
+
+	scratchBuf := &bytes.Buffer{}
+	scratch := NewClientWriter(scratchBuf, option.WriterOptions...)
+	scratch.started = true //BOM由每个文件单独写入
+	if option.Header != nil {
+		scratch.setTableHeader(option.Header)
+	}
+
+	return &RollingWriter{
+		pattern:    pattern,
+		option:     option,
+		header:     option.Header,
+		scratch:    scratch,
+		scratchBuf: scratchBuf,
+		manifest:   &Manifest{Files: make([]ManifestFile, 0)},
+	}, nil
+}
+
+// WriteRow2File write a row
+//
+// structure: a structure or a structure pointer
+func (w *RollingWriter) WriteRow2File(structure interface{}) error {
+	records, err := marshalStructure(structure, false, w.scratch.option)
+	if err != nil {
+		return err
+	}
+	w.useHeader(structure)
+	return w.WriteString2File(records)
+}
+
+// WriteRows2File write rows of a list
+//
+// list: a list or a list pointer,the item of list must be a structure or a structure pointer
+func (w *RollingWriter) WriteRows2File(list interface{}) error {
+	records, err := marshalList(list, false, w.scratch.option)
+	if err != nil {
+		return err
+	}
+	w.useHeader(list)
+	return w.WriteString2File(records)
+}
+
+// WriteString2File write rows,data must not contain the header
+func (w *RollingWriter) WriteString2File(data [][]string) error {
+	if w.closed {
+		return ErrWriterClosed
+	}
+
+	for _, record := range data {
+		row, err := w.format(record)
+		if err != nil {
+			return err
+		}
+
+		if w.current != nil && w.full(int64(len(row))) {
+			err = w.closeFile()
+			if err != nil {
+				return err
+			}
+		}
+		if w.current == nil {
+			err = w.openFile()
+			if err != nil {
+				return err
+			}
+		}
+
+		err = w.write(row)
+		if err != nil {
+			return err
+		}
+		w.current.Rows++
+	}
+	return nil
+}
+
+// Close close the current file and return the manifest of all files
+func (w *RollingWriter) Close() (*Manifest, error) {
+	if w.closed {
+		return w.manifest, ErrWriterClosed
+	}
+	w.closed = true
+
+	if w.current != nil {
+		err := w.closeFile()
+		if err != nil {
+			return w.manifest, err
+		}
+	}
+	return w.manifest, nil
+}
+
+// useHeader use the header of a structure type if the writer has no header
+func (w *RollingWriter) useHeader(v interface{}) {
+	if w.header == nil {
+		w.header = structureTitle(reflect.TypeOf(v), w.scratch.option)
+	}
+	w.scratch.setHeader(v, w.scratch.option)
+}
+
+// full report whether a row of size can not be written to the current file
+func (w *RollingWriter) full(size int64) bool {
+	if w.option.MaxRows > 0 && w.current.Rows >= w.option.MaxRows {
+		return true
+	}
+	return w.option.MaxBytes > 0 && w.current.Rows > 0 && w.current.Bytes+size > w.option.MaxBytes
+}
+
+// format format a record with the options of writer
+func (w *RollingWriter) format(record []string) ([]byte, error) {
+	w.scratchBuf.Reset()
+	err := w.scratch.writeRecords([][]string{record})
+	if err != nil {
+		return nil, err
+	}
+	err = w.scratch.Flush()
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(nil), w.scratchBuf.Bytes()...), nil
+}
+
+func (w *RollingWriter) openFile() error {
+	path := fmt.Sprintf(w.pattern, len(w.manifest.Files)+1)
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0666)
+	if err != nil {
+		return err
+	}
+
+	w.file = file
+	w.buffered = bufio.NewWriter(file)
+	w.current = &ManifestFile{Path: path}
+
+	if w.scratch.option.Excel {
+		err = w.write([]byte(excelPreamble(w.scratch.option, w.scratch.w.Comma)))
+		if err != nil {
+			return err
+		}
+	}
+	if w.header != nil {
+		header, err := w.format(w.header)
+		if err != nil {
+			return err
+		}
+		return w.write(header)
+	}
+	return nil
+}
+
+func (w *RollingWriter) write(p []byte) error {
+	n, err := w.buffered.Write(p)
+	w.current.Bytes += int64(n)
+	return err
+}
+
+func (w *RollingWriter) closeFile() error {
+	err := w.buffered.Flush()
+	if closeErr := w.file.Close(); err == nil {
+		err = closeErr
+	}
+
+	w.manifest.Files = append(w.manifest.Files, *w.current)
+	w.current = nil
+	w.file = nil
+	w.buffered = nil
+	return err
+}
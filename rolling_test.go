@@ -0,0 +1,109 @@
+package easy_csv
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRollingWriterMaxRows(t *testing.T) {
+	dir := t.TempDir()
+	writer, err := NewRollingWriter(filepath.Join(dir, "export_%03d.csv"), WithRollingMaxRows(2))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	list := []testStudentInfo{{Name: "张三", Age: 10}, {Name: "李四", Age: 11}, {Name: "王五", Age: 12}}
+	err = writer.WriteRows2File(list)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	manifest, err := writer.Close()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if len(manifest.Files) != 2 || manifest.Files[0].Rows != 2 || manifest.Files[1].Rows != 1 {
+		t.Errorf("unexpected manifest: %+v", manifest)
+		return
+	}
+	if manifest.Files[1].Path != filepath.Join(dir, "export_002.csv") {
+		t.Errorf("unexpected path: %s", manifest.Files[1].Path)
+	}
+
+	data, err := os.ReadFile(manifest.Files[1].Path)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if string(data) != "name,Age,Grade,分数,邮箱,手机号\n王五,12,,0,,\n" {
+		t.Errorf("unexpected data:\n%s", data)
+	}
+	if manifest.Files[1].Bytes != int64(len(data)) {
+		t.Errorf("unexpected bytes: %d", manifest.Files[1].Bytes)
+	}
+}
+
+func TestRollingWriterMaxBytes(t *testing.T) {
+	dir := t.TempDir()
+	writer, err := NewRollingWriter(filepath.Join(dir, "part-%d.csv"),
+		WithRollingMaxBytes(22), WithRollingHeader([]string{"id", "name"}))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	//表头8字节，每行7字节，每个文件最多两行
+	data := [][]string{{"1", "aaaa"}, {"2", "bbbb"}, {"3", "cccc"}, {"4", "dddd"}, {"5", "eeee"}}
+	err = writer.WriteString2File(data)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	manifest, err := writer.Close()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	if len(manifest.Files) != 3 {
+		t.Errorf("unexpected manifest: %+v", manifest)
+		return
+	}
+	for _, file := range manifest.Files {
+		if file.Bytes > 22 {
+			t.Errorf("file %s exceeds the limit: %d", file.Path, file.Bytes)
+		}
+		info, err := os.Stat(file.Path)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if info.Size() != file.Bytes {
+			t.Errorf("file %s: size %d, manifest %d", file.Path, info.Size(), file.Bytes)
+		}
+	}
+
+	last, err := os.ReadFile(manifest.Files[2].Path)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if string(last) != "id,name\n5,eeee\n" {
+		t.Errorf("unexpected data:\n%s", last)
+	}
+
+	if _, err = writer.Close(); err != ErrWriterClosed {
+		t.Errorf("expected ErrWriterClosed, got %v", err)
+	}
+}
+
+func TestNewRollingWriterPattern(t *testing.T) {
+	_, err := NewRollingWriter("export.csv")
+	if err == nil {
+		t.Error("expected an error for a pattern without verb")
+	}
+}
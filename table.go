@@ -0,0 +1,99 @@
+package easy_csv
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Table writes values of T as csv rows by columns built in code,without a structure with tags for every report.
+//
+//	table := easy_csv.NewTable[order]().
+//		Column("Full name", func(o order) any { return o.FirstName + " " + o.LastName }).
+//		Column("Phone", func(o order) any { return o.Phone }, "phone_desensitization").
+//		Column("Total", func(o order) any { return float64(o.Qty) * o.Price })
+//
+// A column is formatted like a field of structure: nil and nil pointers are empty cells,pointers are dereferenced,
+// and the options of column are the same as the options of csv tag.
+type Table[T any] struct {
+	columns []tableColumn[T]
+}
+
+type tableColumn[T any] struct {
+	tag   csvTag
+	value func(row T) any
+}
+
+func NewTable[T any]() *Table[T] {
+	return &Table[T]{}
+}
+
+// Column add a column.
+//
+// title: name of the column in header
+//
+// value: return the value of the column for a row,it can be computed from several fields
+//
+// options: options of csv tag like "trim", "phone_desensitization", "mask=keep(3,4)" or "encrypt"
+func (table *Table[T]) Column(title string, value func(row T) any, options ...string) *Table[T] {
+	table.columns = append(table.columns, tableColumn[T]{
+		tag:   newTag(title, options),
+		value: value,
+	})
+	return table
+}
+
+// Header return the names of columns
+func (table *Table[T]) Header() []string {
+	header := make([]string, len(table.columns))
+	for i, column := range table.columns {
+		header[i] = column.tag.name
+	}
+	return header
+}
+
+// Record format a row with the options of writer,option can be nil
+func (table *Table[T]) Record(row T, option *ClientWriterOption) ([]string, error) {
+	if len(table.columns) == 0 {
+		return nil, errors.New("table has no column")
+	}
+
+	record := make([]string, len(table.columns))
+	for i, column := range table.columns {
+		cell, err := column.tag.encode(formatValue(column.value(row)), column.tag.name, column.tag.name, option)
+		if err != nil {
+			return nil, fmt.Errorf("column %s: %w", column.tag.name, err)
+		}
+		record[i] = cell
+	}
+	return record, nil
+}
+
+// WriteHeader write the header to writer
+func (table *Table[T]) WriteHeader(writer *ClientWriter) error {
+	writer.setTableHeader(table.Header())
+	return writer.WriteString2File([][]string{table.Header()})
+}
+
+// WriteRow write a row to writer
+func (table *Table[T]) WriteRow(writer *ClientWriter, row T, setTitle ...bool) error {
+	return table.WriteRows(writer, []T{row}, setTitle...)
+}
+
+// WriteRows write rows to writer with the formatting,masking and escaping options of writer
+func (table *Table[T]) WriteRows(writer *ClientWriter, rows []T, setTitle ...bool) error {
+	records := make([][]string, 0, len(rows)+1)
+	if len(setTitle) > 0 && setTitle[0] {
+		records = append(records, table.Header())
+	}
+
+	for _, row := range rows {
+		record, err := table.Record(row, writer.option)
+		if err != nil {
+			return err
+		}
+		records = append(records, record)
+	}
+
+	writer.setTableHeader(table.Header())
+	return writer.WriteString2File(records)
+}
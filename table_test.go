@@ -0,0 +1,61 @@
+package easy_csv
+
+import (
+	"strings"
+	"testing"
+)
+
+type testOrder struct {
+	FirstName string
+	LastName  string
+	Phone     string
+	Qty       int
+	Price     float64
+	Coupon    *string
+}
+
+func TestTable(t *testing.T) {
+	coupon := "VIP"
+	orders := []testOrder{
+		{FirstName: "Jam", LastName: "Joe", Phone: "13322226666", Qty: 2, Price: 1.5, Coupon: &coupon},
+		{FirstName: "Bob", LastName: "Lee", Phone: "13311112222", Qty: 1, Price: 3},
+	}
+
+	table := NewTable[testOrder]().
+		Column("Full name", func(o testOrder) any { return o.FirstName + " " + o.LastName }, "upper").
+		Column("Phone", func(o testOrder) any { return o.Phone }, "phone_desensitization").
+		Column("Total", func(o testOrder) any { return float64(o.Qty) * o.Price }).
+		Column("Coupon", func(o testOrder) any { return o.Coupon })
+
+	buf := &strings.Builder{}
+	writer := NewClientWriter(buf)
+	err := table.WriteRows(writer, orders, true)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	expect := "Full name,Phone,Total,Coupon\n" +
+		"JAM JOE,133****6666,3,VIP\n" +
+		"BOB LEE,133****2222,3,\n"
+	if buf.String() != expect {
+		t.Errorf("unexpected data:\n%s", buf.String())
+	}
+
+	//写入器的脱敏策略同样作用于表格列
+	buf.Reset()
+	writer = NewClientWriter(buf, WithWriterMaskPolicy(&MaskPolicy{DisableTags: true}))
+	err = table.WriteRow(writer, orders[0])
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if buf.String() != "JAM JOE,13322226666,3,VIP\n" {
+		t.Errorf("unexpected data:\n%s", buf.String())
+	}
+
+	_, err = NewTable[testOrder]().Column("Phone", func(o testOrder) any { return o.Phone }, "unknown").Record(orders[0], nil)
+	if err == nil {
+		t.Error("unknown option should be an error")
+	}
+}
@@ -0,0 +1,94 @@
+package easy_csv
+
+import (
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// TransformFunc transform the value of a cell.
+//
+// value string: the value of cell
+//
+// arg string: the text after '=' of the tag option,for example 'a:b' of `replace=a:b`,empty if the option has no '='
+type TransformFunc func(value string, arg string) string
+
+var (
+	transformsMu sync.RWMutex
+	transforms   = map[string]TransformFunc{
+		"trim":    transformTrim,
+		"upper":   transformUpper,
+		"lower":   transformLower,
+		"replace": transformReplace,
+		"squeeze": transformSqueeze,
+	}
+)
+
+// RegisterTransform register a transform by name,so that it can be used as an option of csv tag like the built-in ones.
+// Transforms run in the order of the tag options,before the value is converted on read and after it is formatted on write.
+// A registered transform replaces the transform with the same name.
+func RegisterTransform(name string, fn TransformFunc) {
+	transformsMu.Lock()
+	defer transformsMu.Unlock()
+	transforms[name] = fn
+}
+
+func lookupTransform(name string) (TransformFunc, bool) {
+	transformsMu.RLock()
+	defer transformsMu.RUnlock()
+	fn, ok := transforms[name]
+	return fn, ok
+}
+
+// transform apply the transform options of tag in order
+func (tag csvTag) transform(value string) string {
+	for _, opt := range tag.options {
+		fn, ok := lookupTransform(opt.key)
+		if ok {
+			value = fn(value, opt.value)
+		}
+	}
+	return value
+}
+
+// transformTrim remove leading and trailing white space
+func transformTrim(value string, _ string) string {
+	return strings.TrimSpace(value)
+}
+
+func transformUpper(value string, _ string) string {
+	return strings.ToUpper(value)
+}
+
+func transformLower(value string, _ string) string {
+	return strings.ToLower(value)
+}
+
+// transformReplace replace all old with new,arg is formatted as 'old:new'
+func transformReplace(value string, arg string) string {
+	old, replacement, ok := strings.Cut(arg, ":")
+	if !ok || len(old) == 0 {
+		return value
+	}
+	return strings.ReplaceAll(value, old, replacement)
+}
+
+// transformSqueeze replace every run of white space with a single space
+func transformSqueeze(value string, _ string) string {
+	var builder strings.Builder
+	builder.Grow(len(value))
+
+	inSpace := false
+	for _, r := range value {
+		if unicode.IsSpace(r) {
+			if !inSpace {
+				builder.WriteRune(' ')
+			}
+			inSpace = true
+			continue
+		}
+		inSpace = false
+		builder.WriteRune(r)
+	}
+	return builder.String()
+}
@@ -0,0 +1,50 @@
+package easy_csv
+
+import (
+	"strings"
+	"testing"
+)
+
+type testTransformBean struct {
+	Code string `csv:"code,trim,upper"`
+	Name string `csv:"name,squeeze,replace= :_"`
+	Tags string `csv:"tags,reverse"`
+	Qty  int    `csv:"qty,trim,default=1"`
+}
+
+func TestTransform(t *testing.T) {
+	RegisterTransform("reverse", func(value string, _ string) string {
+		runes := []rune(value)
+		for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+			runes[i], runes[j] = runes[j], runes[i]
+		}
+		return string(runes)
+	})
+
+	tb := testTransformBean{}
+	err := unmarshalOneDSlice([]string{" ab12 ", "Jam   Joe\t Smith", "cba", "  "}, &tb, nil, nil)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if tb.Code != "AB12" || tb.Name != "Jam_Joe_Smith" || tb.Tags != "abc" || tb.Qty != 1 {
+		t.Errorf("unexpected data: %+v", tb)
+	}
+	t.Logf("interface: %+v\n", tb)
+
+	tb = testTransformBean{
+		Code: " xy ",
+		Name: "a  b",
+		Tags: "xyz",
+		Qty:  3,
+	}
+	data, err := marshalStructure(tb, false, nil)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if strings.Join(data[0], ",") != "XY,a_b,zyx,3" {
+		t.Errorf("unexpected data: %v", data)
+	}
+	t.Logf("data: %v\n", data)
+}